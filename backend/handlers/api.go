@@ -1,18 +1,39 @@
 package handlers
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"lansia-backend/services"
 	"net/http"
-	"os/exec"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Output modes for POST /api/tts
+const (
+	OutputSpeaker = "speaker" // Play on the machine running the backend
+	OutputAudio   = "audio"   // Return a WAV body to the client
+	OutputStream  = "stream"  // Chunked WAV body, sent sentence by sentence
 )
 
 type TTSRequest struct {
 	Text   string  `json:"text"`
-	Speed  float64 `json:"speed"`
-	Lang   string  `json:"lang"`
+	Speed  float64 `json:"speed"` // Legacy alias for config.speed
+	Lang   string  `json:"lang"`  // Legacy alias for config.language; missing or "auto" detects it from the text
+	Output string  `json:"output"`
+	// Audio mode only: return JSON with base64 audio and word/sentence timing marks
+	Marks bool `json:"marks"`
+	// Speaker mode only: who owns the audio and how to treat a busy speaker
+	ClientID string `json:"client_id"`
+	Priority string `json:"priority"` // interrupt (default), queue or drop
+	// Full services.TTSConfig; fields present here win over speed/lang
+	Config json.RawMessage `json:"config,omitempty"`
 }
 
 type TTSResponse struct {
@@ -20,9 +41,48 @@ type TTSResponse struct {
 	Duration  float64 `json:"duration_ms,omitempty"`
 	Timestamp string  `json:"timestamp"`
 	Message   string  `json:"message,omitempty"`
+	// Speaker mode only
+	UtteranceID string `json:"utterance_id,omitempty"`
+	State       string `json:"state,omitempty"` // playing, queued or dropped
+	Position    int    `json:"position,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	Language    string `json:"language,omitempty"` // Detected when lang is missing or "auto"
+}
+
+// AudioResponse is the audio mode body when timing marks are requested
+type AudioResponse struct {
+	Success     bool            `json:"success"`
+	Timestamp   string          `json:"timestamp"`
+	ContentType string          `json:"content_type"`
+	Audio       string          `json:"audio"` // Base64
+	AudioLength float64         `json:"audio_duration_ms"`
+	Duration    float64         `json:"duration_ms"`
+	Cached      bool            `json:"cached"`
+	Engine      string          `json:"engine,omitempty"`
+	Marks       []services.Mark `json:"marks"`
+	Language    string          `json:"language,omitempty"`
+	// Mixed-language text only: the parts and the voice language each was read with
+	Languages []services.LanguageSegment `json:"language_segments,omitempty"`
+}
+
+// TTSHandler serves the TTS endpoints on top of an injected services.TTSService
+type TTSHandler struct {
+	service services.TTSService
+	queue   *services.SpeechQueue    // Shares the host speaker between tabs
+	cloud   services.TTSService      // Optional, used when config.use_system_tts is false
+	engines *services.EngineRegistry // Engines behind service, listed by /api/voices
+}
+
+func NewTTSHandler(service services.TTSService, cloud services.TTSService, engines *services.EngineRegistry) *TTSHandler {
+	return &TTSHandler{
+		service: service,
+		queue:   services.NewSpeechQueue(service),
+		cloud:   cloud,
+		engines: engines,
+	}
 }
 
-func TextToSpeechHandler(w http.ResponseWriter, r *http.Request) {
+func (h *TTSHandler) TextToSpeechHandler(w http.ResponseWriter, r *http.Request) {
 	// CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
@@ -52,58 +112,161 @@ func TextToSpeechHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate text length
-	if len(text) > 5000 {
+	// Validate text length (in characters, not bytes)
+	if utf8.RuneCountInString(text) > services.MaxTextRunes {
 		respondJSON(w, http.StatusBadRequest, TTSResponse{
 			Success: false,
-			Message: "Text too long (max 5000 characters)",
+			Message: fmt.Sprintf("Text too long (max %d characters)", services.MaxTextRunes),
 		})
 		return
 	}
 
-	// Start timing
-	startTime := time.Now()
+	// Validate output mode
+	output := strings.ToLower(strings.TrimSpace(req.Output))
+	if output == "" {
+		output = OutputSpeaker
+	}
+	if output != OutputSpeaker && output != OutputAudio && output != OutputStream {
+		respondJSON(w, http.StatusBadRequest, TTSResponse{
+			Success: false,
+			Message: "Unknown output mode (use \"speaker\", \"audio\" or \"stream\")",
+		})
+		return
+	}
 
-	// Execute TTS based on OS
-	var cmd *exec.Cmd
-	var err error
-	
-	switch runtime.GOOS {
-	case "darwin": // macOS
-		cmd = exec.Command("say", cleanTextForTTS(text))
-	case "linux": // Linux
-		cmd = exec.Command("espeak", "-v", "id", cleanTextForTTS(text))
-	case "windows": // Windows
-		psScript := `Add-Type -AssemblyName System.speech; $speak = New-Object System.Speech.Synthesis.SpeechSynthesizer; $speak.Speak("` + escapeForPowerShell(cleanTextForTTS(text)) + `")`
-		cmd = exec.Command("powershell", "-Command", psScript)
-	default:
-		respondJSON(w, http.StatusInternalServerError, TTSResponse{
+	priority, err := services.ParsePriority(req.Priority)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, TTSResponse{
 			Success: false,
-			Message: "Unsupported operating system",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	// Run TTS command
-	if err = cmd.Run(); err != nil {
-		respondJSON(w, http.StatusInternalServerError, TTSResponse{
+	config, err := resolveConfig(req)
+	if err == nil {
+		err = checkEngine(h.engines, config)
+	}
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, TTSResponse{
 			Success: false,
-			Message: "Failed to speak text: " + err.Error(),
+			Message: err.Error(),
 		})
 		return
 	}
 
-	// Calculate duration
-	duration := time.Since(startTime).Seconds() * 1000
+	// Start sending audio as soon as the first sentence is rendered
+	if output == OutputStream {
+		h.streamAudio(w, r, text, config)
+		return
+	}
+
+	// Render to WAV and let the extension play it in the tab
+	if output == OutputAudio {
+		allowRender(w, text)
+		audio, err := h.serviceFor(config).Synthesize(r.Context(), text, config)
+		if err != nil {
+			respondJSON(w, http.StatusInternalServerError, TTSResponse{
+				Success: false,
+				Message: "Failed to render speech: " + err.Error(),
+			})
+			return
+		}
 
+		if req.Marks {
+			// Offsets must point into the text the client sent, not the trimmed copy
+			leading := utf8.RuneCountInString(req.Text) - utf8.RuneCountInString(strings.TrimLeftFunc(req.Text, unicode.IsSpace))
+			marks := make([]services.Mark, len(audio.Marks))
+			for i, mark := range audio.Marks {
+				mark.Start += leading
+				mark.End += leading
+				marks[i] = mark
+			}
+			segments := make([]services.LanguageSegment, len(audio.Languages))
+			for i, segment := range audio.Languages {
+				segment.Start += leading
+				segment.End += leading
+				segments[i] = segment
+			}
+
+			respondJSON(w, http.StatusOK, AudioResponse{
+				Success:     true,
+				Timestamp:   time.Now().Format(time.RFC3339),
+				ContentType: audio.ContentType,
+				Audio:       base64.StdEncoding.EncodeToString(audio.Data),
+				AudioLength: audio.AudioDuration,
+				Duration:    audio.Duration,
+				Cached:      audio.Cached,
+				Engine:      audio.Engine,
+				Marks:       marks,
+				Language:    audio.Language,
+				Languages:   segments,
+			})
+			return
+		}
+
+		if audio.Cached {
+			w.Header().Set("X-TTS-Cache", "hit")
+		} else {
+			w.Header().Set("X-TTS-Cache", "miss")
+		}
+		if audio.Engine != "" {
+			w.Header().Set("X-TTS-Engine", audio.Engine)
+		}
+		if audio.Language != "" {
+			w.Header().Set("X-TTS-Language", audio.Language)
+		}
+		respondAudio(w, audio.ContentType, audio.Data, audio.Duration)
+		return
+	}
+
+	// Play on the backend host, sharing the speaker with other tabs.
+	// Cloud voices only render audio, so the speaker always uses local engines.
+	startTime := time.Now()
+	result, err := h.queue.Enqueue(services.TTSRequest{
+		Text:     text,
+		Config:   config,
+		ClientID: req.ClientID,
+		Priority: priority,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrQueueFull) {
+			status = http.StatusTooManyRequests
+		}
+		respondJSON(w, status, TTSResponse{
+			Success: false,
+			Message: "Failed to speak text: " + err.Error(),
+		})
+		return
+	}
+
+	messages := map[string]string{
+		services.QueueStatePlaying: "Speech started",
+		services.QueueStateQueued:  "Speech queued",
+		services.QueueStateDropped: "Speech dropped, speaker is busy",
+	}
 	respondJSON(w, http.StatusOK, TTSResponse{
-		Success:   true,
-		Duration:  duration,
-		Timestamp: time.Now().Format(time.RFC3339),
-		Message:   "Text spoken successfully",
+		Success:     true,
+		Duration:    time.Since(startTime).Seconds() * 1000,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Message:     messages[result.State],
+		UtteranceID: result.ID,
+		State:       result.State,
+		Position:    result.Position,
+		Owner:       result.Owner,
+		Language:    detectedLanguage(text, config),
 	})
 }
 
+// serviceFor picks the cloud service only when the client opts out of system TTS
+func (h *TTSHandler) serviceFor(config services.TTSConfig) services.TTSService {
+	if !config.UseSystemTTS && h.cloud != nil {
+		return h.cloud
+	}
+	return h.service
+}
+
 func HealthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	
@@ -116,18 +279,34 @@ func HealthCheck(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func GetVoicesHandler(w http.ResponseWriter, r *http.Request) {
+func (h *TTSHandler) GetVoicesHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	
-	voices := []string{"id-ID", "en-US", "en-GB"}
-	if runtime.GOOS == "darwin" {
-		voices = append(voices, "id", "en", "es", "fr")
+	engines := h.engines.Info()
+	voices := []string{}
+	for _, engine := range engines {
+		for _, voice := range engine.Voices {
+			voices = append(voices, voice.Name)
+		}
+	}
+	if len(voices) == 0 {
+		// Engine could not list voices, fall back to the language codes we always map
+		voices = []string{"id-ID", "en-US", "en-GB"}
 	}
 	
-	respondJSON(w, http.StatusOK, map[string]interface{}{
+	response := map[string]interface{}{
 		"voices": voices,
 		"default": "id-ID",
-	})
+		"engines": engines, // In fallback order, with capabilities and circuit breaker state
+	}
+	if h.cloud != nil {
+		// Used with config.use_system_tts = false
+		if cloudVoices, err := h.cloud.GetVoices(); err == nil {
+			response["cloud_voices"] = cloudVoices
+		}
+	}
+	
+	respondJSON(w, http.StatusOK, response)
 }
 
 func GetConfigHandler(w http.ResponseWriter, r *http.Request) {
@@ -150,19 +329,67 @@ func GetConfigHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // Helper functions
-func escapeForPowerShell(text string) string {
-	text = strings.ReplaceAll(text, `"`, `\"`)
-	text = strings.ReplaceAll(text, "$", "`$")
-	text = strings.ReplaceAll(text, "'", "`'")
-	return text
+
+// checkEngine rejects a config.engine that is not registered and enabled
+func checkEngine(engines *services.EngineRegistry, config services.TTSConfig) error {
+	if config.Engine != "" && config.UseSystemTTS && !engines.Enabled(config.Engine) {
+		return fmt.Errorf("Unknown engine %q (see /api/voices)", config.Engine)
+	}
+	return nil
+}
+
+// detectedLanguage returns the language the text will be read in when it is
+// detected, or "" when the client chose one
+func detectedLanguage(text string, config services.TTSConfig) string {
+	if config.Language != "" && !strings.EqualFold(config.Language, services.LanguageAuto) {
+		return ""
+	}
+	language, _ := services.DetectLanguage(text)
+	return language
+}
+
+// resolveConfig merges the legacy {speed, lang} fields and the full config object
+func resolveConfig(req TTSRequest) (services.TTSConfig, error) {
+	config := services.GetDefaultConfig()
+	config.Language = services.LanguageAuto
+	if req.Speed != 0 {
+		config.Speed = req.Speed
+	}
+	if req.Lang != "" {
+		config.Language = req.Lang
+	}
+
+	// Decode on top of the defaults so omitted fields keep their value
+	if len(req.Config) > 0 {
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			return config, errors.New("Invalid config object")
+		}
+	}
+
+	if config.Speed < 0.5 || config.Speed > 2.0 {
+		return config, errors.New("Speed must be between 0.5 and 2.0")
+	}
+	if config.Volume < 0 || config.Volume > 1.0 {
+		return config, errors.New("Volume must be between 0.0 and 1.0")
+	}
+	if config.ReadAs != "" && config.ReadAs != services.ReadAsDigits && config.ReadAs != services.ReadAsSpell {
+		return config, errors.New("read_as must be digits or spell")
+	}
+	switch config.Verbosity {
+	case "", services.VerbosityMinimal, services.VerbosityNormal, services.VerbosityVerbose:
+	default:
+		return config, errors.New("verbosity must be minimal, normal or verbose")
+	}
+	return config, nil
 }
 
-func cleanTextForTTS(text string) string {
-	// Remove extra whitespace
-	text = strings.Join(strings.Fields(text), " ")
-	// Escape quotes
-	text = strings.ReplaceAll(text, `"`, `\"`)
-	return text
+func respondAudio(w http.ResponseWriter, contentType string, audio []byte, durationMs float64) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(audio)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-TTS-Duration-Ms", strconv.FormatFloat(durationMs, 'f', 0, 64))
+	w.WriteHeader(http.StatusOK)
+	w.Write(audio)
 }
 
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
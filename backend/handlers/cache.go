@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"lansia-backend/services"
+	"net/http"
+	"time"
+)
+
+// CacheHandler exposes statistics and purging of the rendered audio cache
+type CacheHandler struct {
+	cache *services.AudioCache
+}
+
+func NewCacheHandler(cache *services.AudioCache) *CacheHandler {
+	return &CacheHandler{cache: cache}
+}
+
+func (h *CacheHandler) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"cache":     h.cache.Stats(),
+	})
+}
+
+func (h *CacheHandler) PurgeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	removed := h.cache.Purge()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"removed":   removed,
+		"cache":     h.cache.Stats(),
+	})
+}
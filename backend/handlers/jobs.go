@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"lansia-backend/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// JobResponse is the public view of a job (without the full source text)
+type JobResponse struct {
+	ID              string            `json:"id"`
+	State           services.JobState `json:"state"`
+	Progress        float64           `json:"progress"`
+	ChunksDone      int               `json:"chunks_done"`
+	ChunksTotal     int               `json:"chunks_total"`
+	TextLength      int               `json:"text_length"`
+	AudioDurationMs float64           `json:"audio_duration_ms,omitempty"`
+	AudioURL        string            `json:"audio_url,omitempty"`
+	CaptionsURL     string            `json:"captions_url,omitempty"`
+	Error           string            `json:"error,omitempty"`
+	CreatedAt       string            `json:"created_at"`
+	UpdatedAt       string            `json:"updated_at"`
+}
+
+// JobHandler serves the asynchronous synthesis job API
+type JobHandler struct {
+	jobs    *services.JobManager
+	engines *services.EngineRegistry // Validates config.engine like /api/tts
+}
+
+func NewJobHandler(jobs *services.JobManager, engines *services.EngineRegistry) *JobHandler {
+	return &JobHandler{jobs: jobs, engines: engines}
+}
+
+func (h *JobHandler) CreateJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Same body as POST /api/tts; output and priority are ignored
+	var req TTSRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		respondError(w, http.StatusBadRequest, "Text cannot be empty")
+		return
+	}
+
+	config, err := resolveConfig(req)
+	if err == nil {
+		err = checkEngine(h.engines, config)
+	}
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := h.jobs.Create(text, config)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Location", "/api/jobs/"+job.ID)
+	respondJSON(w, http.StatusAccepted, newJobResponse(job))
+}
+
+func (h *JobHandler) ListJobsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	jobs := h.jobs.List()
+	responses := make([]JobResponse, 0, len(jobs))
+	for i := range jobs {
+		responses = append(responses, newJobResponse(&jobs[i]))
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"jobs": responses,
+	})
+}
+
+func (h *JobHandler) GetJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	job, err := h.jobs.Get(mux.Vars(r)["id"])
+	if err != nil {
+		respondJobError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, newJobResponse(job))
+}
+
+func (h *JobHandler) GetJobAudioHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	path, err := h.jobs.AudioPath(mux.Vars(r)["id"])
+	if err != nil {
+		respondJobError(w, err)
+		return
+	}
+
+	// ServeFile handles Range requests, so long audio can be seeked
+	w.Header().Set("Content-Type", "audio/wav")
+	http.ServeFile(w, r, path)
+}
+
+// GetJobCaptionsHandler returns sentence captions for the job audio,
+// as WebVTT (default) or SRT with ?format=srt
+func (h *JobHandler) GetJobCaptionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	id := mux.Vars(r)["id"]
+	cues, err := h.jobs.Captions(id)
+	if err != nil {
+		respondJobError(w, err)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	body, contentType, err := services.FormatCaptions(cues, format)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if format == "" {
+		format = services.CaptionVTT
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+id+`.`+format+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func (h *JobHandler) CancelJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	job, err := h.jobs.Cancel(mux.Vars(r)["id"])
+	if err != nil {
+		respondJobError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, newJobResponse(job))
+}
+
+func (h *JobHandler) DeleteJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := h.jobs.Delete(mux.Vars(r)["id"]); err != nil {
+		respondJobError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newJobResponse(job *services.Job) JobResponse {
+	response := JobResponse{
+		ID:              job.ID,
+		State:           job.State,
+		Progress:        job.Progress,
+		ChunksDone:      job.ChunksDone,
+		ChunksTotal:     len(job.Chunks),
+		TextLength:      len([]rune(job.Text)),
+		AudioDurationMs: job.AudioDurationMs,
+		Error:           job.Error,
+		CreatedAt:       job.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       job.UpdatedAt.Format(time.RFC3339),
+	}
+	if job.State == services.JobCompleted {
+		response.AudioURL = "/api/jobs/" + job.ID + "/audio"
+		response.CaptionsURL = "/api/jobs/" + job.ID + "/captions"
+	}
+	return response
+}
+
+func respondJobError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrJobNotFound), errors.Is(err, services.ErrNoCaptions):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrJobNotReady):
+		respondError(w, http.StatusConflict, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func respondError(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, status, map[string]interface{}{
+		"success": false,
+		"message": message,
+	})
+}
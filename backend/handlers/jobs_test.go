@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"lansia-backend/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateJobRejectsUnknownEngine(t *testing.T) {
+	engines := services.NewEngineRegistry(services.NewBuiltinEngine())
+	jobs, err := services.NewJobManager(t.TempDir(), services.NewSystemTTSService(engines), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewJobHandler(jobs, engines)
+
+	body := `{"text":"Halo semua.","config":{"engine":"piper"}}`
+	recorder := httptest.NewRecorder()
+	handler.CreateJobHandler(recorder, httptest.NewRequest(http.MethodPost, "/api/jobs", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+	if !strings.Contains(recorder.Body.String(), "Unknown engine") {
+		t.Errorf("body = %s, want an unknown engine error", recorder.Body)
+	}
+	if list := jobs.List(); len(list) != 0 {
+		t.Errorf("%d jobs stored for a rejected request", len(list))
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"lansia-backend/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// maxLexiconImportBytes limits the body of POST /api/lexicon/import
+const maxLexiconImportBytes = 4 << 20
+
+// LexiconHandler serves CRUD, import and export of the pronunciation lexicon
+type LexiconHandler struct {
+	lexicon *services.Lexicon
+}
+
+func NewLexiconHandler(lexicon *services.Lexicon) *LexiconHandler {
+	return &LexiconHandler{lexicon: lexicon}
+}
+
+// ListLexiconHandler returns all entries, or only those that apply to
+// ?language= and ?site= when either is given
+func (h *LexiconHandler) ListLexiconHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	language := r.URL.Query().Get("language")
+	site := r.URL.Query().Get("site")
+
+	entries := []services.LexiconEntry{}
+	for _, entry := range h.lexicon.List() {
+		if (language == "" && site == "") || entry.Applies(language, site) {
+			entries = append(entries, entry)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"entries":   entries,
+	})
+}
+
+func (h *LexiconHandler) CreateLexiconHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req services.LexiconEntry
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	entry, err := h.lexicon.Create(req)
+	if err != nil {
+		respondLexiconError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/lexicon/"+entry.ID)
+	respondJSON(w, http.StatusCreated, entry)
+}
+
+func (h *LexiconHandler) GetLexiconHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	entry, err := h.lexicon.Get(mux.Vars(r)["id"])
+	if err != nil {
+		respondLexiconError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, entry)
+}
+
+func (h *LexiconHandler) UpdateLexiconHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req services.LexiconEntry
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	entry, err := h.lexicon.Update(mux.Vars(r)["id"], req)
+	if err != nil {
+		respondLexiconError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, entry)
+}
+
+func (h *LexiconHandler) DeleteLexiconHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := h.lexicon.Delete(mux.Vars(r)["id"]); err != nil {
+		respondLexiconError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ExportLexiconHandler downloads all entries as JSON (default) or CSV with ?format=csv
+func (h *LexiconHandler) ExportLexiconHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	entries := h.lexicon.List()
+	switch lexiconFormat(r) {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="lexicon.csv"`)
+		services.WriteLexiconCSV(w, entries)
+	case "json":
+		w.Header().Set("Content-Disposition", `attachment; filename="lexicon.json"`)
+		respondJSON(w, http.StatusOK, entries)
+	default:
+		respondError(w, http.StatusBadRequest, "Unknown format (use json or csv)")
+	}
+}
+
+// ImportLexiconHandler adds entries from a JSON or CSV body (?format=csv, or
+// a text/csv Content-Type). Entries with the same pattern and scope are
+// updated; ?mode=replace drops all existing entries first.
+func (h *LexiconHandler) ImportLexiconHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	mode := r.URL.Query().Get("mode")
+	if mode != "" && mode != "merge" && mode != "replace" {
+		respondError(w, http.StatusBadRequest, "Unknown mode (use merge or replace)")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxLexiconImportBytes))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "Import file too large")
+		return
+	}
+
+	var entries []services.LexiconEntry
+	switch lexiconFormat(r) {
+	case "csv":
+		entries, err = services.ParseLexiconCSV(bytes.NewReader(body))
+	case "json":
+		entries, err = services.ParseLexiconJSON(body)
+	default:
+		respondError(w, http.StatusBadRequest, "Unknown format (use json or csv)")
+		return
+	}
+	if err != nil {
+		respondLexiconError(w, err)
+		return
+	}
+
+	imported, err := h.lexicon.Import(entries, mode == "replace")
+	if err != nil {
+		respondLexiconError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"imported":  imported,
+		"total":     len(h.lexicon.List()),
+	})
+}
+
+// lexiconFormat picks json or csv from ?format=, falling back to the Content-Type
+func lexiconFormat(r *http.Request) string {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		return format
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		return "csv"
+	}
+	return "json"
+}
+
+func respondLexiconError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrLexiconNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidLexicon):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"lansia-backend/services"
+	"math"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// openAIPCMRate is the sample rate OpenAI clients expect for response_format=pcm
+const openAIPCMRate = 24000
+
+// openAIModels are accepted as-is and use the normal engine fallback chain.
+// A registered engine name (e.g. "piper") can be given as the model instead.
+var openAIModels = map[string]bool{
+	"tts-1":           true,
+	"tts-1-hd":        true,
+	"gpt-4o-mini-tts": true,
+}
+
+// openAIVoices have no local equivalent, so they use the default voice
+var openAIVoices = map[string]bool{
+	"alloy": true, "ash": true, "ballad": true, "coral": true, "echo": true, "fable": true,
+	"nova": true, "onyx": true, "sage": true, "shimmer": true, "verse": true,
+}
+
+// openAICompressedFormats are OpenAI response formats that need an encoder
+// this server does not have
+var openAICompressedFormats = map[string]bool{
+	"mp3": true, "opus": true, "aac": true, "flac": true,
+}
+
+// OpenAISpeechRequest is the body of POST /v1/audio/speech
+type OpenAISpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	Speed          float64 `json:"speed"`           // 0.25 - 4.0, clamped to what the engines support
+	ResponseFormat string  `json:"response_format"` // wav (default here) or pcm
+	Instructions   string  `json:"instructions"`    // Accepted and ignored
+	Language       string  `json:"language"`        // Not part of the OpenAI API; defaults to id
+}
+
+// openAIError mirrors the OpenAI error body so client libraries can show it
+type openAIError struct {
+	Error struct {
+		Message string  `json:"message"`
+		Type    string  `json:"type"`
+		Param   *string `json:"param"`
+		Code    *string `json:"code"`
+	} `json:"error"`
+}
+
+// OpenAISpeechHandler serves the OpenAI audio speech API on top of the local
+// engines, so tools written against it can use this backend offline
+func (h *TTSHandler) OpenAISpeechHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req OpenAISpeechRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondOpenAIError(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	text := strings.TrimSpace(req.Input)
+	if text == "" {
+		respondOpenAIError(w, http.StatusBadRequest, "input cannot be empty", "input")
+		return
+	}
+	if utf8.RuneCountInString(text) > services.MaxTextRunes {
+		respondOpenAIError(w, http.StatusBadRequest, fmt.Sprintf("input too long (max %d characters)", services.MaxTextRunes), "input")
+		return
+	}
+
+	// Unlike OpenAI (mp3), the default is wav: no encoder is bundled for the
+	// compressed formats, so those are rejected instead of sent mislabelled
+	format := strings.ToLower(req.ResponseFormat)
+	if format == "" {
+		format = "wav"
+	}
+	if openAICompressedFormats[format] {
+		respondOpenAIError(w, http.StatusBadRequest, fmt.Sprintf("response_format %q is not supported by this server; use \"wav\" (the default here, not mp3) or \"pcm\"", req.ResponseFormat), "response_format")
+		return
+	}
+	if format != "wav" && format != "pcm" {
+		respondOpenAIError(w, http.StatusBadRequest, fmt.Sprintf("response_format %q is not supported (use \"wav\" or \"pcm\")", req.ResponseFormat), "response_format")
+		return
+	}
+
+	config := services.GetDefaultConfig()
+	if req.Language != "" {
+		config.Language = req.Language
+	}
+
+	switch {
+	case req.Model == "" || openAIModels[req.Model]:
+	case h.engines.Enabled(req.Model):
+		config.Engine = req.Model
+	default:
+		respondOpenAIError(w, http.StatusBadRequest, fmt.Sprintf("Unknown model %q (use tts-1 or an engine from /api/voices)", req.Model), "model")
+		return
+	}
+
+	if req.Voice != "" && !openAIVoices[strings.ToLower(req.Voice)] {
+		config.Voice = req.Voice
+	}
+
+	if req.Speed != 0 {
+		if req.Speed < 0.25 || req.Speed > 4.0 {
+			respondOpenAIError(w, http.StatusBadRequest, "speed must be between 0.25 and 4.0", "speed")
+			return
+		}
+		config.Speed = math.Max(0.5, math.Min(2.0, req.Speed))
+	}
+
+	allowRender(w, text)
+	audio, err := h.service.Synthesize(r.Context(), text, config)
+	if err != nil {
+		respondOpenAIError(w, http.StatusInternalServerError, "Failed to render speech: "+err.Error(), "")
+		return
+	}
+
+	if audio.Engine != "" {
+		w.Header().Set("X-TTS-Engine", audio.Engine)
+	}
+	if format == "pcm" {
+		pcm, err := services.ResamplePCM(audio.Data, openAIPCMRate)
+		if err != nil {
+			respondOpenAIError(w, http.StatusInternalServerError, "Failed to convert audio: "+err.Error(), "")
+			return
+		}
+		respondAudio(w, "audio/pcm", pcm, audio.Duration)
+		return
+	}
+	respondAudio(w, audio.ContentType, audio.Data, audio.Duration)
+}
+
+func respondOpenAIError(w http.ResponseWriter, status int, message, param string) {
+	var body openAIError
+	body.Error.Message = message
+	body.Error.Type = "invalid_request_error"
+	if status >= 500 {
+		body.Error.Type = "server_error"
+	}
+	if param != "" {
+		body.Error.Param = &param
+	}
+	respondJSON(w, status, body)
+}
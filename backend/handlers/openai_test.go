@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"lansia-backend/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAISpeechResponseFormat(t *testing.T) {
+	engines := services.NewEngineRegistry(services.NewBuiltinEngine())
+	handler := NewTTSHandler(services.NewSystemTTSService(engines), nil, engines)
+
+	tests := []struct {
+		format      string
+		status      int
+		contentType string
+		message     string
+	}{
+		{"", http.StatusOK, "audio/wav", ""},
+		{"pcm", http.StatusOK, "audio/pcm", ""},
+		{"mp3", http.StatusBadRequest, "", "the default here, not mp3"},
+		{"opus", http.StatusBadRequest, "", `use \"wav\"`},
+		{"aac", http.StatusBadRequest, "", `use \"wav\"`},
+		{"flac", http.StatusBadRequest, "", `use \"wav\"`},
+		{"ogg", http.StatusBadRequest, "", `use \"wav\" or \"pcm\"`},
+	}
+	for _, tt := range tests {
+		body := `{"model":"tts-1","input":"Halo.","voice":"alloy","response_format":"` + tt.format + `"}`
+		recorder := httptest.NewRecorder()
+		handler.OpenAISpeechHandler(recorder, httptest.NewRequest(http.MethodPost, "/v1/audio/speech", strings.NewReader(body)))
+
+		if recorder.Code != tt.status {
+			t.Errorf("format %q: status = %d, want %d (%s)", tt.format, recorder.Code, tt.status, recorder.Body)
+			continue
+		}
+		if tt.contentType != "" && recorder.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("format %q: Content-Type = %q, want %q", tt.format, recorder.Header().Get("Content-Type"), tt.contentType)
+		}
+		if tt.message != "" && !strings.Contains(recorder.Body.String(), tt.message) {
+			t.Errorf("format %q: body = %s, want it to mention %s", tt.format, recorder.Body, tt.message)
+		}
+	}
+}
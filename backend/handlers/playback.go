@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lansia-backend/services"
+	"net/http"
+	"time"
+)
+
+type PlaybackResponse struct {
+	Success   bool                       `json:"success"`
+	Timestamp string                     `json:"timestamp"`
+	Message   string                     `json:"message,omitempty"`
+	Status    *services.TTSStatus        `json:"status,omitempty"`
+	Queue     []services.QueuedUtterance `json:"queue,omitempty"`
+}
+
+// PlaybackRequest is the optional body of the control endpoints
+type PlaybackRequest struct {
+	ClientID string `json:"client_id"`
+}
+
+func (h *TTSHandler) StopHandler(w http.ResponseWriter, r *http.Request) {
+	h.playbackControl(w, r, h.queue.StopClient, "Speech stopped")
+}
+
+func (h *TTSHandler) PauseHandler(w http.ResponseWriter, r *http.Request) {
+	h.playbackControl(w, r, h.queue.PauseClient, "Speech paused")
+}
+
+func (h *TTSHandler) ResumeHandler(w http.ResponseWriter, r *http.Request) {
+	h.playbackControl(w, r, h.queue.ResumeClient, "Speech resumed")
+}
+
+func (h *TTSHandler) NextHandler(w http.ResponseWriter, r *http.Request) {
+	h.playbackControl(w, r, h.queue.NextClient, "Skipped to next sentence")
+}
+
+func (h *TTSHandler) PreviousHandler(w http.ResponseWriter, r *http.Request) {
+	h.playbackControl(w, r, h.queue.PreviousClient, "Back to previous sentence")
+}
+
+func (h *TTSHandler) RepeatHandler(w http.ResponseWriter, r *http.Request) {
+	h.playbackControl(w, r, h.queue.RepeatClient, "Repeating current sentence")
+}
+
+func (h *TTSHandler) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	status := h.queue.Status()
+	respondJSON(w, http.StatusOK, PlaybackResponse{
+		Success:   true,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Status:    &status,
+		Queue:     h.queue.Pending(),
+	})
+}
+
+// EventsHandler streams sentence and word events of the host speaker as
+// Server-Sent Events, so a page can highlight what is being read.
+// With ?client_id= only that client's utterances are reported.
+func (h *TTSHandler) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	keepWriting(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	clientID := r.URL.Query().Get("client_id")
+	ticker := time.NewTicker(eventInterval)
+	defer ticker.Stop()
+
+	var last services.TTSStatus
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		status := h.queue.Status()
+		if clientID != "" && status.ClientID != clientID {
+			status = services.TTSStatus{}
+		}
+
+		sameUtterance := status.Speaking && last.Speaking && status.StartedAt != nil &&
+			last.StartedAt != nil && status.StartedAt.Equal(*last.StartedAt)
+		switch {
+		case !status.Speaking && last.Speaking:
+			writeEvent(w, "end", map[string]interface{}{"text": last.Text})
+		case !status.Speaking:
+		case !sameUtterance || status.SentenceIndex != last.SentenceIndex:
+			writeEvent(w, services.MarkSentence, services.Mark{
+				Type:  services.MarkSentence,
+				Text:  status.SentenceText,
+				Start: status.SentenceStart,
+				End:   status.SentenceEnd,
+			})
+			fallthrough
+		case status.Word != nil && (last.Word == nil || status.Word.Start != last.Word.Start):
+			if status.Word != nil {
+				writeEvent(w, services.MarkWord, status.Word)
+			}
+		}
+		flusher.Flush()
+		last = status
+	}
+}
+
+// eventInterval is how often EventsHandler samples the speaker status
+const eventInterval = 100 * time.Millisecond
+
+func writeEvent(w http.ResponseWriter, event string, data interface{}) {
+	payload, _ := json.Marshal(data)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
+
+// playbackControl runs a playback or navigation action for a client and reports the new status.
+// The client ID comes from ?client_id= or the JSON body; empty means global control.
+func (h *TTSHandler) playbackControl(w http.ResponseWriter, r *http.Request, action func(clientID string) error, message string) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	clientID := r.URL.Query().Get("client_id")
+	if clientID == "" && r.ContentLength != 0 {
+		var req PlaybackRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondJSON(w, http.StatusBadRequest, PlaybackResponse{
+				Success:   false,
+				Timestamp: time.Now().Format(time.RFC3339),
+				Message:   "Invalid request body",
+			})
+			return
+		}
+		clientID = req.ClientID
+	}
+
+	if err := action(clientID); err != nil {
+		respondJSON(w, playbackErrorStatus(err), PlaybackResponse{
+			Success:   false,
+			Timestamp: time.Now().Format(time.RFC3339),
+			Message:   err.Error(),
+		})
+		return
+	}
+
+	status := h.queue.Status()
+	respondJSON(w, http.StatusOK, PlaybackResponse{
+		Success:   true,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Message:   message,
+		Status:    &status,
+	})
+}
+
+func playbackErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNotSpeaking), errors.Is(err, services.ErrNotOwner),
+		errors.Is(err, services.ErrNoSentence):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrNotSupported):
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lansia-backend/services"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// StreamControl is a client message on /api/tts/ws: {"type":"speak", ...TTSRequest} or {"type":"stop"}
+type StreamControl struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"` // Optional, echoed back in events
+	TTSRequest
+}
+
+// StreamEvent is a server message on /api/tts/ws. Every "chunk" event is
+// followed by one binary frame holding that chunk as a complete WAV file.
+type StreamEvent struct {
+	Type    string                `json:"type"` // start, chunk, end, stopped or error
+	ID      string                `json:"id,omitempty"`
+	Chunks  int                   `json:"chunks,omitempty"`
+	Chunk   *services.StreamChunk `json:"chunk,omitempty"`
+	Message string                `json:"message,omitempty"`
+	// start only, when the language is detected from the text
+	Language string `json:"language,omitempty"`
+}
+
+// streamAudio answers output "stream": one WAV body whose PCM is written
+// sentence by sentence while the rest is still being rendered
+func (h *TTSHandler) streamAudio(w http.ResponseWriter, r *http.Request, text string, config services.TTSConfig) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	keepWriting(w)
+	writer := services.NewWAVStreamWriter(w)
+	started := false
+	err := services.StreamAudio(r.Context(), h.serviceFor(config), text, config, func(chunk services.StreamChunk) error {
+		if !started {
+			w.Header().Set("Content-Type", "audio/wav")
+			w.Header().Set("Cache-Control", "no-store")
+			if language := detectedLanguage(text, config); language != "" {
+				w.Header().Set("X-TTS-Language", language)
+			}
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if err := writer.WriteWAV(chunk.Audio); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err == nil || r.Context().Err() != nil {
+		return
+	}
+
+	// Once audio has been sent the status code is gone, so just end the stream
+	if started {
+		log.Printf("TTS stream aborted: %v", err)
+		return
+	}
+	respondError(w, http.StatusInternalServerError, "Failed to render speech: "+err.Error())
+}
+
+// StreamWebSocketHandler streams audio over a WebSocket. Each "speak" message
+// interrupts the previous one; "stop" cancels it.
+func (h *TTSHandler) StreamWebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgradeWebSocket(w, r)
+	if errors.Is(err, errForbiddenOrigin) {
+		respondError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer conn.Close()
+
+	send := func(event StreamEvent) error {
+		payload, _ := json.Marshal(event)
+		return conn.WriteMessage(opText, payload)
+	}
+
+	// Only this read loop touches the current stream; the stream goroutine
+	// just writes frames, which wsConn serializes
+	var (
+		cancel  context.CancelFunc = func() {}
+		current string
+		done    = make(chan struct{})
+	)
+	close(done)
+
+	stop := func() {
+		cancel()
+		<-done
+	}
+	defer stop()
+
+	sequence := 0
+	for {
+		op, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if op != opText {
+			send(StreamEvent{Type: "error", Message: "Expected a JSON text message"})
+			continue
+		}
+
+		var control StreamControl
+		if err := json.Unmarshal(message, &control); err != nil {
+			send(StreamEvent{Type: "error", Message: "Invalid message"})
+			continue
+		}
+
+		switch control.Type {
+		case "stop":
+			stop()
+			send(StreamEvent{Type: "stopped", ID: current})
+
+		case "speak":
+			text, config, err := validateStream(control.TTSRequest)
+			if err == nil {
+				err = checkEngine(h.engines, config)
+			}
+			if err != nil {
+				send(StreamEvent{Type: "error", ID: control.ID, Message: err.Error()})
+				continue
+			}
+
+			// A new utterance interrupts the one still streaming
+			stop()
+
+			sequence++
+			id := control.ID
+			if id == "" {
+				id = strconv.Itoa(sequence)
+			}
+
+			ctx, cancelStream := context.WithCancel(r.Context())
+			finished := make(chan struct{})
+			cancel, current, done = cancelStream, id, finished
+
+			go func() {
+				defer close(finished)
+				defer cancelStream()
+
+				send(StreamEvent{Type: "start", ID: id, Chunks: len(services.StreamSegments(text)), Language: detectedLanguage(text, config)})
+				err := services.StreamAudio(ctx, h.serviceFor(config), text, config, func(chunk services.StreamChunk) error {
+					if err := send(StreamEvent{Type: "chunk", ID: id, Chunk: &chunk}); err != nil {
+						return err
+					}
+					return conn.WriteMessage(opBinary, chunk.Audio)
+				})
+				switch {
+				case ctx.Err() != nil:
+					// Stopped or interrupted, the caller reports it
+				case err != nil:
+					send(StreamEvent{Type: "error", ID: id, Message: "Failed to render speech: " + err.Error()})
+				default:
+					send(StreamEvent{Type: "end", ID: id})
+				}
+			}()
+
+		default:
+			send(StreamEvent{Type: "error", Message: fmt.Sprintf("Unknown message type %q", control.Type)})
+		}
+	}
+}
+
+// keepWriting lifts the server's write timeout for responses that stream
+// for as long as the audio (or the client) lasts
+func keepWriting(w http.ResponseWriter) {
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+}
+
+// WriteTimeout is the server's write timeout for ordinary responses
+const WriteTimeout = 30 * time.Second
+
+// renderTimePerRune is how much longer than WriteTimeout a synchronous
+// render may take per rune of text
+const renderTimePerRune = 10 * time.Millisecond
+
+// allowRender moves the write deadline so that a long text can finish
+// rendering before the response is written
+func allowRender(w http.ResponseWriter, text string) {
+	allowance := WriteTimeout + time.Duration(utf8.RuneCountInString(text))*renderTimePerRune
+	http.NewResponseController(w).SetWriteDeadline(time.Now().Add(allowance))
+}
+
+// validateStream applies the /api/tts text and config checks to a WebSocket request
+func validateStream(req TTSRequest) (string, services.TTSConfig, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return "", services.TTSConfig{}, fmt.Errorf("Text cannot be empty")
+	}
+	if utf8.RuneCountInString(text) > services.MaxTextRunes {
+		return "", services.TTSConfig{}, fmt.Errorf("Text too long (max %d characters)", services.MaxTextRunes)
+	}
+	config, err := resolveConfig(req)
+	return text, config, err
+}
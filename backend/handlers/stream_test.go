@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAllowRenderOutlivesWriteTimeout(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowRender(w, "teks panjang")
+		time.Sleep(200 * time.Millisecond) // Render takes longer than the write timeout
+		io.WriteString(w, "audio")
+	}))
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("render cut off by write timeout: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "audio" {
+		t.Errorf("body = %q, %v; want audio", body, err)
+	}
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Minimal RFC 6455 server side: enough for the extension to receive
+// binary audio frames and exchange JSON control messages.
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxMessageBytes caps a single client message (text is limited far below this anyway)
+const maxMessageBytes = 1 << 20
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+var errMessageTooLarge = errors.New("websocket message too large")
+
+// errForbiddenOrigin rejects handshakes from web pages outside AllowedOrigins
+var errForbiddenOrigin = errors.New("websocket origin not allowed")
+
+// AllowedOrigins lists the browser origins that may call the API: the
+// extension and local development pages. main.go uses it for CORS; WebSocket
+// handshakes are checked against it too, since CORS does not cover them.
+// A "*" matches any text, as in the CORS options.
+var AllowedOrigins = []string{
+	"chrome-extension://*", // Chrome extensions
+	"moz-extension://*",    // Firefox extensions
+	"http://localhost:*",   // Local development
+	"http://127.0.0.1:*",
+}
+
+// originAllowed reports whether a handshake may proceed. Requests without an
+// Origin come from native clients, not from a page another site controls.
+func originAllowed(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+		prefix, suffix, wildcard := strings.Cut(allowed, "*")
+		if !wildcard {
+			if origin == allowed {
+				return true
+			}
+			continue
+		}
+		if len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+}
+
+// upgradeWebSocket performs the opening handshake and takes over the connection
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		return nil, errors.New("not a websocket handshake")
+	}
+	if !originAllowed(r.Header.Get("Origin")) {
+		return nil, errForbiddenOrigin
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection cannot be upgraded")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	// The server's read/write timeouts would otherwise end long-lived sockets
+	conn.SetDeadline(time.Time{})
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, reader: rw.Reader}, nil
+}
+
+// ReadMessage returns the next text or binary message, answering pings on the way
+func (c *wsConn) ReadMessage() (int, []byte, error) {
+	var message []byte
+	messageOp := -1
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.writeFrame(opClose, payload)
+			return 0, nil, io.EOF
+		case opText, opBinary:
+			messageOp = op
+			message = payload
+		case opContinuation:
+			if messageOp < 0 {
+				return 0, nil, errors.New("unexpected continuation frame")
+			}
+			if len(message)+len(payload) > maxMessageBytes {
+				return 0, nil, errMessageTooLarge
+			}
+			message = append(message, payload...)
+		default:
+			return 0, nil, errors.New("unknown websocket opcode")
+		}
+
+		if fin {
+			return messageOp, message, nil
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (bool, int, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := head[0]&0x80 != 0
+	op := int(head[0] & 0x0F)
+	masked := head[1]&0x80 != 0
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxMessageBytes {
+		return false, 0, nil, errMessageTooLarge
+	}
+
+	// Clients must mask every frame
+	if !masked {
+		return false, 0, nil, errors.New("unmasked client frame")
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// WriteMessage sends one unfragmented frame; safe for concurrent use
+func (c *wsConn) WriteMessage(op int, payload []byte) error {
+	return c.writeFrame(op, payload)
+}
+
+func (c *wsConn) writeFrame(op int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	header := []byte{0x80 | byte(op)}
+	switch {
+	case len(payload) < 126:
+		header = append(header, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(len(payload)))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(len(payload)))
+	}
+
+	if _, err := c.conn.Write(header); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(payload)
+	return err
+}
+
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
+
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginAllowed(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"chrome-extension://abcdefghijklmnop", true},
+		{"moz-extension://1234-5678", true},
+		{"http://localhost:3000", true},
+		{"http://LOCALHOST:8080", true},
+		{"http://127.0.0.1:5500", true},
+		{"https://evil.example", false},
+		{"http://localhost.evil.example", false},
+		{"http://127.0.0.1.evil.example", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		if got := originAllowed(tt.origin); got != tt.want {
+			t.Errorf("originAllowed(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestStreamWebSocketRejectsForeignOrigin(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/tts/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Origin", "https://evil.example")
+
+	recorder := httptest.NewRecorder()
+	(&TTSHandler{}).StreamWebSocketHandler(recorder, req)
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+}
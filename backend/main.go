@@ -2,8 +2,13 @@ package main
 
 import (
 	"lansia-backend/handlers"
+	"lansia-backend/services"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -11,25 +16,142 @@ import (
 )
 
 func main() {
+	dataDir := getEnv("LANSIA_DATA_DIR", "data")
+
+	// Rendered audio is cached on disk so repeated phrases skip the engine
+	cacheTTL, err := time.ParseDuration(getEnv("LANSIA_CACHE_TTL", "720h"))
+	if err != nil {
+		log.Fatal("Invalid LANSIA_CACHE_TTL:", err)
+	}
+	cacheMB, err := strconv.ParseInt(getEnv("LANSIA_CACHE_MAX_MB", "256"), 10, 64)
+	if err != nil {
+		log.Fatal("Invalid LANSIA_CACHE_MAX_MB:", err)
+	}
+	audioCache, err := services.NewAudioCache(filepath.Join(dataDir, "cache"), cacheMB<<20, cacheTTL)
+	if err != nil {
+		log.Fatal("Failed to open audio cache:", err)
+	}
+
+	// Long-lived engine workers avoid forking espeak for every render
+	workers, err := strconv.Atoi(getEnv("LANSIA_ENGINE_WORKERS", "2"))
+	if err != nil {
+		log.Fatal("Invalid LANSIA_ENGINE_WORKERS:", err)
+	}
+	var pool *services.EnginePool
+	if workers > 0 {
+		if pool, err = services.NewEnginePool(workers); err != nil {
+			log.Printf("Engine pool disabled, espeak starts a new process per sentence: %v", err)
+			pool = nil
+		}
+	}
+
+	// Engines are tried in this order unless LANSIA_ENGINES says otherwise;
+	// ones that are not installed on this host are skipped
+	engineList := []services.Engine{
+		services.NewPiperEngine(getEnv("LANSIA_PIPER_BIN", "piper"), getEnv("LANSIA_PIPER_MODELS", filepath.Join(dataDir, "piper"))),
+	}
+	if httpConfig, ok := httpEngineConfig(); ok {
+		httpEngine, err := services.NewHTTPEngine(httpConfig)
+		if err != nil {
+			log.Fatal("Invalid HTTP TTS engine:", err)
+		}
+		engineList = append(engineList, httpEngine)
+	}
+	engineList = append(engineList,
+		services.NewEspeakEngine(pool),
+		services.NewFestivalEngine(),
+		services.NewSayEngine(),
+		services.NewSAPIEngine(),
+		services.NewBuiltinEngine(),
+	)
+	engines := services.NewEngineRegistry(engineList...)
+	if order := os.Getenv("LANSIA_ENGINES"); order != "" {
+		if err := engines.SetOrder(strings.Split(order, ",")); err != nil {
+			log.Fatal("Invalid LANSIA_ENGINES:", err)
+		}
+	}
+
+	// TTS services (cloud is optional and only used on request)
+	systemService := services.CreateTTSService(false, "", engines)
+
+	// On Linux desktops the speaker can go through speech-dispatcher instead,
+	// using the user's own voice settings; rendering still uses the engines
+	var speakerService services.TTSService = systemService
+	if getEnv("LANSIA_SPEAKER", "engines") == "speechd" {
+		socket := getEnv("LANSIA_SPEECHD_SOCKET", services.SpeechDispatcherSocket())
+		speakerService = services.NewSpeechDispatcherService(socket, systemService)
+		log.Printf("Speaker output via speech-dispatcher at %s", socket)
+	}
+
+	// User pronunciation fixes are applied outside the cache, so editing the
+	// lexicon changes the cache key instead of serving stale audio
+	lexicon, err := services.NewLexicon(filepath.Join(dataDir, "lexicon.json"))
+	if err != nil {
+		log.Fatal("Failed to open pronunciation lexicon:", err)
+	}
+
+	// Language detection (lang missing or "auto") runs outermost, so every
+	// detected part gets the lexicon entries and cache key of its language
+	localService := services.NewLexiconTTSService(services.NewCachedTTSService(speakerService, audioCache, "system"), lexicon)
+	var ttsService services.TTSService = services.NewLanguageTTSService(localService)
+	var cloudService services.TTSService
+	if apiKey := os.Getenv("GOOGLE_TTS_API_KEY"); apiKey != "" {
+		// Quota and network errors fall back to the local engines
+		cloud := services.NewCloudTTSService(apiKey, os.Getenv("GOOGLE_TTS_BASE_URL"), localService)
+		cloudService = services.NewLanguageTTSService(services.NewLexiconTTSService(services.NewCachedTTSService(cloud, audioCache, "cloud"), lexicon))
+	}
+	ttsHandler := handlers.NewTTSHandler(ttsService, cloudService, engines)
+	cacheHandler := handlers.NewCacheHandler(audioCache)
+	lexiconHandler := handlers.NewLexiconHandler(lexicon)
+
+	// Background synthesis jobs survive restarts in the data directory
+	jobManager, err := services.NewJobManager(filepath.Join(dataDir, "jobs"), ttsService, 1)
+	if err != nil {
+		log.Fatal("Failed to open job store:", err)
+	}
+	jobHandler := handlers.NewJobHandler(jobManager, engines)
+
 	// Create router
 	r := mux.NewRouter()
 
 	// API Routes
-	r.HandleFunc("/api/tts", handlers.TextToSpeechHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tts", ttsHandler.TextToSpeechHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tts/stop", ttsHandler.StopHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tts/pause", ttsHandler.PauseHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tts/resume", ttsHandler.ResumeHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tts/next", ttsHandler.NextHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tts/previous", ttsHandler.PreviousHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tts/repeat", ttsHandler.RepeatHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tts/ws", ttsHandler.StreamWebSocketHandler).Methods("GET")
+	r.HandleFunc("/api/tts/events", ttsHandler.EventsHandler).Methods("GET")
+	r.HandleFunc("/api/tts/status", ttsHandler.StatusHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/jobs", jobHandler.CreateJobHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/jobs", jobHandler.ListJobsHandler).Methods("GET")
+	r.HandleFunc("/api/jobs/{id}", jobHandler.GetJobHandler).Methods("GET")
+	r.HandleFunc("/api/jobs/{id}", jobHandler.DeleteJobHandler).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/api/jobs/{id}/audio", jobHandler.GetJobAudioHandler).Methods("GET")
+	r.HandleFunc("/api/jobs/{id}/captions", jobHandler.GetJobCaptionsHandler).Methods("GET")
+	r.HandleFunc("/api/jobs/{id}/cancel", jobHandler.CancelJobHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/cache", cacheHandler.StatsHandler).Methods("GET")
+	r.HandleFunc("/api/cache", cacheHandler.PurgeHandler).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/api/lexicon", lexiconHandler.ListLexiconHandler).Methods("GET")
+	r.HandleFunc("/api/lexicon", lexiconHandler.CreateLexiconHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/lexicon/export", lexiconHandler.ExportLexiconHandler).Methods("GET")
+	r.HandleFunc("/api/lexicon/import", lexiconHandler.ImportLexiconHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/lexicon/{id}", lexiconHandler.GetLexiconHandler).Methods("GET")
+	r.HandleFunc("/api/lexicon/{id}", lexiconHandler.UpdateLexiconHandler).Methods("PUT", "OPTIONS")
+	r.HandleFunc("/api/lexicon/{id}", lexiconHandler.DeleteLexiconHandler).Methods("DELETE")
 	r.HandleFunc("/api/health", handlers.HealthCheck).Methods("GET", "OPTIONS")
-	r.HandleFunc("/api/voices", handlers.GetVoicesHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/voices", ttsHandler.GetVoicesHandler).Methods("GET", "OPTIONS")
 	r.HandleFunc("/api/config", handlers.GetConfigHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/v1/audio/speech", ttsHandler.OpenAISpeechHandler).Methods("POST", "OPTIONS")
 
 	// CORS configuration for Chrome Extension
 	c := cors.New(cors.Options{
-		AllowedOrigins: []string{
-			"chrome-extension://*",     // Chrome extensions
-			"moz-extension://*",        // Firefox extensions
-			"http://localhost:*",       // Local development
-			"http://127.0.0.1:*",
-		},
+		AllowedOrigins:   handlers.AllowedOrigins, // Extensions and local development
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Requested-With"},
+		ExposedHeaders:   []string{"X-TTS-Duration-Ms", "X-TTS-Cache", "X-TTS-Engine", "X-TTS-Language"},
 		AllowCredentials: true,
 		MaxAge:           86400,
 		Debug:            false,
@@ -39,19 +161,75 @@ func main() {
 	server := &http.Server{
 		Handler:      c.Handler(r),
 		Addr:         ":8080",
-		WriteTimeout: 30 * time.Second,
+		WriteTimeout: handlers.WriteTimeout, // Audio renders extend it per request
 		ReadTimeout:  30 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}
 
 	log.Println("🚀 Lansia Friendly Backend starting on :8080")
+	log.Printf("🔊 Engines: %s", strings.Join(engines.Order(), ", "))
 	log.Println("📌 Endpoints:")
-	log.Println("   POST /api/tts     - Text to Speech")
+	log.Println("   POST /api/tts     - Text to Speech (output: speaker | audio)")
+	log.Println("   POST /api/tts/stop    - Stop current speech")
+	log.Println("   POST /api/tts/pause   - Pause current speech")
+	log.Println("   POST /api/tts/resume  - Resume paused speech")
+	log.Println("   POST /api/tts/next    - Skip to next sentence")
+	log.Println("   POST /api/tts/previous - Back to previous sentence")
+	log.Println("   POST /api/tts/repeat  - Repeat current sentence")
+	log.Println("   GET  /api/tts/status  - Current utterance and progress")
+	log.Println("   GET  /api/tts/events  - Live sentence/word events (SSE)")
+	log.Println("   GET  /api/tts/ws      - WebSocket audio streaming")
+	log.Println("   POST /api/jobs    - Create background synthesis job")
+	log.Println("   GET  /api/jobs/{id}         - Job progress")
+	log.Println("   GET  /api/jobs/{id}/audio   - Finished job audio")
+	log.Println("   GET  /api/jobs/{id}/captions - WebVTT/SRT captions (?format=srt)")
+	log.Println("   POST /api/jobs/{id}/cancel  - Cancel job")
+	log.Println("   GET  /api/cache   - Audio cache statistics")
+	log.Println("   DELETE /api/cache - Purge audio cache")
+	log.Println("   GET  /api/lexicon - Pronunciation lexicon (POST to add, PUT/DELETE /{id})")
+	log.Println("   GET  /api/lexicon/export - Export lexicon (?format=csv)")
+	log.Println("   POST /api/lexicon/import - Import lexicon JSON or CSV (?mode=replace)")
 	log.Println("   GET  /api/health  - Health Check")
 	log.Println("   GET  /api/voices  - Available Voices")
 	log.Println("   GET  /api/config  - Extension Configuration")
+	log.Println("   POST /v1/audio/speech - OpenAI-compatible speech (wav or pcm)")
 
 	if err := server.ListenAndServe(); err != nil {
 		log.Fatal("Server failed:", err)
 	}
+}
+
+// httpEngineConfig reads the optional HTTP TTS server (e.g. MaryTTS on the LAN)
+// from LANSIA_HTTP_TTS_*; a preset fills in the defaults for known servers
+func httpEngineConfig() (services.HTTPEngineConfig, bool) {
+	config := services.HTTPEngineConfig{}
+	switch preset := os.Getenv("LANSIA_HTTP_TTS_PRESET"); preset {
+	case "":
+		if os.Getenv("LANSIA_HTTP_TTS_URL") == "" {
+			return config, false
+		}
+	case "marytts":
+		config = services.MaryTTSPreset
+	default:
+		log.Fatalf("Unknown LANSIA_HTTP_TTS_PRESET %q", preset)
+	}
+
+	config.Name = getEnv("LANSIA_HTTP_TTS_NAME", config.Name)
+	config.URL = getEnv("LANSIA_HTTP_TTS_URL", config.URL)
+	config.Method = getEnv("LANSIA_HTTP_TTS_METHOD", config.Method)
+	config.Params = getEnv("LANSIA_HTTP_TTS_PARAMS", config.Params)
+	config.Format = getEnv("LANSIA_HTTP_TTS_FORMAT", config.Format)
+	config.VoicesURL = getEnv("LANSIA_HTTP_TTS_VOICES_URL", config.VoicesURL)
+	if languages := os.Getenv("LANSIA_HTTP_TTS_LANGUAGES"); languages != "" {
+		config.Languages = strings.Split(languages, ",")
+	}
+	return config, true
+}
+
+// getEnv reads an environment variable with a fallback value
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
 }
\ No newline at end of file
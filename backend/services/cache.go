@@ -0,0 +1,392 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStats statistik cache audio
+type CacheStats struct {
+	Entries   int     `json:"entries"`
+	Bytes     int64   `json:"bytes"`
+	MaxBytes  int64   `json:"max_bytes"`
+	TTL       string  `json:"ttl"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	HitRate   float64 `json:"hit_rate"`
+	Evictions int64   `json:"evictions"`
+}
+
+// cacheMeta metadata yang disimpan di samping file audio
+type cacheMeta struct {
+	Text          string    `json:"text"`
+	ContentType   string    `json:"content_type"`
+	AudioDuration float64   `json:"audio_duration_ms"`
+	Marks         []Mark    `json:"marks,omitempty"`
+	Engine        string    `json:"engine,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type cacheEntry struct {
+	key       string
+	size      int64
+	createdAt time.Time
+	element   *list.Element
+}
+
+// AudioCache cache audio hasil render di disk, dengan key hash dari teks
+// yang sudah dinormalisasi dan semua parameter yang mempengaruhi suara.
+//
+// Tiap entry disimpan sebagai <dir>/<key[:2]>/<key>.wav dan <key>.json.
+// Entry yang paling lama tidak dipakai dibuang saat ukuran melewati maxBytes,
+// dan entry yang lebih tua dari ttl dianggap tidak ada.
+type AudioCache struct {
+	dir      string
+	maxBytes int64
+	ttl      time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry
+	lru       *list.List // Depan = paling baru dipakai
+	bytes     int64
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// NewAudioCache membuka cache di dir dan memuat entry yang sudah ada
+func NewAudioCache(dir string, maxBytes int64, ttl time.Duration) (*AudioCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	c := &AudioCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		entries:  map[string]*cacheEntry{},
+		lru:      list.New(),
+	}
+
+	// Urutan LRU dipulihkan dari waktu modifikasi file (disentuh setiap hit)
+	type found struct {
+		entry   *cacheEntry
+		touched time.Time
+	}
+	existing := []found{}
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".wav" {
+			return nil
+		}
+		key := strings.TrimSuffix(filepath.Base(path), ".wav")
+		meta, err := c.readMeta(key)
+		if err != nil {
+			c.removeFiles(key)
+			return nil
+		}
+		existing = append(existing, found{
+			entry:   &cacheEntry{key: key, size: info.Size(), createdAt: meta.CreatedAt},
+			touched: info.ModTime(),
+		})
+		return nil
+	})
+	sort.Slice(existing, func(i, j int) bool {
+		return existing[i].touched.Before(existing[j].touched)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range existing {
+		f.entry.element = c.lru.PushFront(f.entry)
+		c.entries[f.entry.key] = f.entry
+		c.bytes += f.entry.size
+	}
+	c.evictLocked()
+
+	return c, nil
+}
+
+// CacheKey menghitung key cache dari teks yang dinormalisasi, parameter suara
+// dan entri leksikon yang berlaku (mengubah leksikon berarti key baru)
+func CacheKey(engine, text string, config TTSConfig) string {
+	config = applyConfigDefaults(config)
+	parts := []string{
+		engine,
+		cleanText(text),
+		strings.ToLower(config.Language),
+		config.Voice,
+		config.Engine,
+		fmt.Sprintf("%.2f", config.Speed),
+		fmt.Sprintf("%.2f", config.Volume),
+	}
+	if config.ReadAs != "" {
+		parts = append(parts, "read_as="+config.ReadAs)
+	}
+	if config.Verbosity != "" && config.Verbosity != VerbosityNormal {
+		parts = append(parts, "verbosity="+config.Verbosity)
+	}
+	if config.lexicon != nil {
+		parts = append(parts, config.lexicon.key)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get mengambil audio dari cache. Mark disesuaikan dengan teks yang diminta
+// karena teks dengan spasi berbeda memakai entry yang sama.
+func (c *AudioCache) Get(key, text string) (*AudioResult, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && c.ttl > 0 && time.Since(entry.createdAt) > c.ttl {
+		c.removeLocked(entry)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		c.mu.Unlock()
+		return nil, false
+	}
+	c.lru.MoveToFront(entry.element)
+	c.mu.Unlock()
+
+	data, err := os.ReadFile(c.audioPath(key))
+	meta, metaErr := c.readMeta(key)
+	if err != nil || metaErr != nil {
+		c.mu.Lock()
+		c.misses++
+		if current, ok := c.entries[key]; ok {
+			c.removeLocked(current)
+		}
+		c.mu.Unlock()
+		return nil, false
+	}
+
+	c.mu.Lock()
+	c.hits++
+	c.mu.Unlock()
+
+	// Simpan waktu pakai di disk supaya urutan LRU bertahan setelah restart
+	now := time.Now()
+	os.Chtimes(c.audioPath(key), now, now)
+
+	marks := meta.Marks
+	if meta.Text != text {
+		marks = remapMarks(marks, meta.Text, text)
+	}
+	return &AudioResult{
+		Data:          data,
+		ContentType:   meta.ContentType,
+		AudioDuration: meta.AudioDuration,
+		Marks:         marks,
+		Engine:        meta.Engine,
+	}, true
+}
+
+// Put menyimpan audio ke cache lalu membuang entry lama jika cache penuh
+func (c *AudioCache) Put(key, text string, audio *AudioResult) error {
+	size := int64(len(audio.Data))
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return nil // Terlalu besar untuk di-cache
+	}
+
+	meta, err := json.Marshal(cacheMeta{
+		Text:          text,
+		ContentType:   audio.ContentType,
+		AudioDuration: audio.AudioDuration,
+		Marks:         audio.Marks,
+		Engine:        audio.Engine,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.audioPath(key)), 0o755); err != nil {
+		return err
+	}
+	// Metadata dulu, file audio menandakan entry sudah lengkap
+	if err := writeFileAtomic(c.metaPath(key), meta); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.audioPath(key), audio.Data); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[key]; ok {
+		c.lru.Remove(old.element)
+		c.bytes -= old.size
+	}
+	entry := &cacheEntry{key: key, size: size, createdAt: time.Now()}
+	entry.element = c.lru.PushFront(entry)
+	c.entries[key] = entry
+	c.bytes += size
+	c.evictLocked()
+	return nil
+}
+
+// Purge menghapus semua entry dan mengembalikan jumlah yang dihapus
+func (c *AudioCache) Purge() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := len(c.entries)
+	for _, entry := range c.entries {
+		c.removeFiles(entry.key)
+	}
+	c.entries = map[string]*cacheEntry{}
+	c.lru.Init()
+	c.bytes = 0
+	return count
+}
+
+// Stats mengembalikan statistik cache saat ini
+func (c *AudioCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CacheStats{
+		Entries:   len(c.entries),
+		Bytes:     c.bytes,
+		MaxBytes:  c.maxBytes,
+		TTL:       c.ttl.String(),
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRate = float64(c.hits) / float64(total)
+	}
+	return stats
+}
+
+// evictLocked membuang entry yang kadaluarsa dan yang paling lama tidak dipakai
+func (c *AudioCache) evictLocked() {
+	for element := c.lru.Back(); element != nil; {
+		prev := element.Prev()
+		entry := element.Value.(*cacheEntry)
+		if c.ttl > 0 && time.Since(entry.createdAt) > c.ttl {
+			c.removeLocked(entry)
+		}
+		element = prev
+	}
+	for c.maxBytes > 0 && c.bytes > c.maxBytes && c.lru.Len() > 0 {
+		c.removeLocked(c.lru.Back().Value.(*cacheEntry))
+		c.evictions++
+	}
+}
+
+func (c *AudioCache) removeLocked(entry *cacheEntry) {
+	c.lru.Remove(entry.element)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+	c.removeFiles(entry.key)
+}
+
+func (c *AudioCache) removeFiles(key string) {
+	os.Remove(c.audioPath(key))
+	os.Remove(c.metaPath(key))
+}
+
+func (c *AudioCache) readMeta(key string) (*cacheMeta, error) {
+	data, err := os.ReadFile(c.metaPath(key))
+	if err != nil {
+		return nil, err
+	}
+	var meta cacheMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+func (c *AudioCache) audioPath(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".wav")
+}
+
+func (c *AudioCache) metaPath(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// remapMarks memindahkan offset mark dari teks lama ke teks baru yang kata-katanya
+// sama tapi spasinya berbeda. Jika kata-katanya tidak cocok, mark dibuang.
+func remapMarks(marks []Mark, oldText, newText string) []Mark {
+	oldWords := splitWords(Segment{Text: oldText})
+	newWords := splitWords(Segment{Text: newText})
+	if len(oldWords) != len(newWords) {
+		return nil
+	}
+
+	starts := map[int]int{}
+	ends := map[int]int{}
+	for i := range oldWords {
+		starts[oldWords[i].Start] = newWords[i].Start
+		ends[oldWords[i].End] = newWords[i].End
+	}
+
+	remapped := make([]Mark, 0, len(marks))
+	for _, mark := range marks {
+		start, okStart := starts[mark.Start]
+		end, okEnd := ends[mark.End]
+		if !okStart || !okEnd {
+			return nil
+		}
+		mark.Start, mark.End = start, end
+		mark.Text = string([]rune(newText)[start:end])
+		remapped = append(remapped, mark)
+	}
+	return remapped
+}
+
+// CachedTTSService membungkus TTSService sehingga Synthesize memakai AudioCache.
+// Method lain (Speak, kontrol playback, navigasi) diteruskan ke service asli.
+type CachedTTSService struct {
+	TTSService
+	cache  *AudioCache
+	engine string
+}
+
+// NewCachedTTSService membungkus service; engine ikut menjadi bagian key cache
+func NewCachedTTSService(service TTSService, cache *AudioCache, engine string) *CachedTTSService {
+	return &CachedTTSService{TTSService: service, cache: cache, engine: engine}
+}
+
+// Synthesize mengembalikan audio dari cache, atau merender lalu menyimpannya
+func (c *CachedTTSService) Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error) {
+	startTime := time.Now()
+	key := CacheKey(c.engine, text, config)
+	if audio, ok := c.cache.Get(key, text); ok {
+		audio.Duration = time.Since(startTime).Seconds() * 1000
+		audio.Cached = true
+		return audio, nil
+	}
+
+	audio, err := c.TTSService.Synthesize(ctx, text, config)
+	if err != nil {
+		return nil, err
+	}
+	// Audio dari service cadangan tidak disimpan dengan key engine ini
+	if audio.Fallback {
+		return audio, nil
+	}
+	if err := c.cache.Put(key, text, audio); err != nil {
+		log.Printf("Cache: failed to store %s: %v", key[:12], err)
+	}
+	return audio, nil
+}
+
+// Unwrap mengembalikan service yang dibungkus
+func (c *CachedTTSService) Unwrap() TTSService {
+	return c.TTSService
+}
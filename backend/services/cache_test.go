@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyEngine engine palsu yang gagal sebanyak failures kali lalu berhasil
+type flakyEngine struct {
+	mu       sync.Mutex
+	failures int
+	renders  int
+}
+
+func (e *flakyEngine) Name() string { return "flaky" }
+
+func (e *flakyEngine) Capabilities() EngineCapabilities {
+	return EngineCapabilities{Languages: []string{"id"}, Formats: []string{"wav"}}
+}
+
+func (e *flakyEngine) Available() bool { return true }
+
+func (e *flakyEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.renders++
+	if e.failures > 0 {
+		e.failures--
+		return nil, errors.New("engine belum siap")
+	}
+	return testWAV(16000, 1600), nil
+}
+
+func (e *flakyEngine) Voices() ([]Voice, error) { return nil, nil }
+
+func TestCacheSkipsFallbackEngineAudio(t *testing.T) {
+	cache, err := NewAudioCache(t.TempDir(), 1<<20, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flaky := &flakyEngine{failures: 1}
+	registry := NewEngineRegistry(flaky, NewBuiltinEngine())
+	service := NewCachedTTSService(NewSystemTTSService(registry), cache, "system")
+	config := TTSConfig{Language: "id-ID", Speed: 1.0, Volume: 1.0}
+
+	// Engine utama gagal, builtin menggantikan dan hasilnya tidak di-cache
+	audio, err := service.Synthesize(context.Background(), "Halo semua.", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audio.Engine != "builtin" || !audio.Fallback {
+		t.Fatalf("first render = %s (fallback %v), want builtin fallback", audio.Engine, audio.Fallback)
+	}
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Fatalf("fallback audio cached: %+v", stats)
+	}
+
+	// Setelah engine utama pulih, hasilnya dirender dan di-cache
+	audio, err = service.Synthesize(context.Background(), "Halo semua.", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audio.Engine != "flaky" || audio.Fallback || audio.Cached {
+		t.Fatalf("second render = %+v, want fresh audio from flaky", audio)
+	}
+	audio, err = service.Synthesize(context.Background(), "Halo semua.", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !audio.Cached || audio.Engine != "flaky" {
+		t.Errorf("third render = %+v, want cached flaky audio", audio)
+	}
+}
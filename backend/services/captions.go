@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Format caption yang didukung
+const (
+	CaptionVTT = "vtt"
+	CaptionSRT = "srt"
+)
+
+// maxCueRunes kalimat yang lebih panjang dari ini dipecah menjadi beberapa cue
+// supaya tetap terbaca di layar (sekitar dua baris teks)
+const maxCueRunes = 84
+
+// BuildCues membuat cue caption per kalimat dari mark hasil sintesis.
+// Kalimat yang terlalu panjang dipecah mengikuti mark kata di dalamnya.
+func BuildCues(marks []Mark) []Mark {
+	cues := []Mark{}
+	for i, mark := range marks {
+		if mark.Type != MarkSentence {
+			continue
+		}
+		if len([]rune(mark.Text)) <= maxCueRunes {
+			cues = append(cues, mark)
+			continue
+		}
+
+		words := []Mark{}
+		for _, word := range marks[i+1:] {
+			if word.Type != MarkWord {
+				break
+			}
+			words = append(words, word)
+		}
+		if len(words) == 0 {
+			cues = append(cues, mark)
+			continue
+		}
+		cues = append(cues, groupWordCues(words)...)
+	}
+	return cues
+}
+
+// groupWordCues menggabungkan kata berurutan menjadi cue tidak lebih dari maxCueRunes
+func groupWordCues(words []Mark) []Mark {
+	cues := []Mark{}
+	var cue *Mark
+	for _, word := range words {
+		if cue != nil && len([]rune(cue.Text))+1+len([]rune(word.Text)) <= maxCueRunes {
+			cue.Text += " " + word.Text
+			cue.End = word.End
+			cue.DurationMs = word.TimeMs + word.DurationMs - cue.TimeMs
+			continue
+		}
+		cues = append(cues, Mark{
+			Type:       MarkSentence,
+			Text:       word.Text,
+			Start:      word.Start,
+			End:        word.End,
+			TimeMs:     word.TimeMs,
+			DurationMs: word.DurationMs,
+		})
+		cue = &cues[len(cues)-1]
+	}
+	return cues
+}
+
+// FormatCaptions menulis cue sebagai WebVTT atau SRT, beserta content type-nya
+func FormatCaptions(cues []Mark, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case CaptionVTT, "":
+		buf.WriteString("WEBVTT\n\n")
+		for i, cue := range cues {
+			fmt.Fprintf(&buf, "%d\n%s --> %s\n%s\n\n", i+1,
+				captionTime(cue.TimeMs, "."), captionTime(cue.TimeMs+cue.DurationMs, "."), escapeVTT(cue.Text))
+		}
+		return buf.Bytes(), "text/vtt; charset=utf-8", nil
+	case CaptionSRT:
+		for i, cue := range cues {
+			fmt.Fprintf(&buf, "%d\n%s --> %s\n%s\n\n", i+1,
+				captionTime(cue.TimeMs, ","), captionTime(cue.TimeMs+cue.DurationMs, ","), cue.Text)
+		}
+		return buf.Bytes(), "application/x-subrip; charset=utf-8", nil
+	}
+	return nil, "", fmt.Errorf("unknown caption format %q (use \"vtt\" or \"srt\")", format)
+}
+
+// captionTime memformat milidetik sebagai HH:MM:SS.mmm (SRT memakai koma)
+func captionTime(ms float64, separator string) string {
+	total := int64(ms + 0.5)
+	if total < 0 {
+		total = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d",
+		total/3600000, total/60000%60, total/1000%60, separator, total%1000)
+}
+
+// escapeVTT meng-escape karakter yang punya arti khusus di teks cue WebVTT
+func escapeVTT(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxTextRunes batas panjang teks untuk satu request TTS (dihitung per rune)
+	MaxTextRunes = 100000
+	// defaultChunkRunes panjang maksimal satu potongan yang dirender sekaligus
+	defaultChunkRunes = 1000
+	// speakSegmentRunes panjang maksimal satu kalimat yang diputar di host
+	speakSegmentRunes = 400
+)
+
+// Segment potongan teks beserta posisinya di teks asli.
+// Offset dihitung dalam rune, Start inklusif dan End eksklusif.
+type Segment struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Singkatan yang tidak pernah mengakhiri kalimat (gelar, alamat, satuan)
+var nonTerminalAbbreviations = map[string]bool{
+	"jl": true, "jln": true, "gg": true, "no": true, "rt": true, "rw": true,
+	"kel": true, "kec": true, "kab": true, "kp": true, "ds": true, "prov": true,
+	"bpk": true, "bp": true, "sdr": true, "sdri": true, "yth": true,
+	"tn": true, "ny": true, "nn": true, "dr": true, "drs": true, "dra": true,
+	"prof": true, "ir": true, "h": true, "hj": true, "kh": true, "st": true,
+	"pt": true, "cv": true, "rp": true, "tgl": true, "hlm": true,
+	"a.n": true, "u.p": true, "d.a": true, "s.d": true, "u.b": true,
+	"mr": true, "mrs": true, "ms": true, "vs": true, "e.g": true, "i.e": true,
+}
+
+// Singkatan yang bisa berada di akhir kalimat ("... buku, pensil, dll.")
+var terminalAbbreviations = map[string]bool{
+	"dll": true, "dsb": true, "dst": true, "dkk": true, "tsb": true,
+	"tbk": true, "etc": true, "inc": true, "ltd": true, "co": true,
+}
+
+// SplitSentences memecah teks menjadi kalimat dengan aturan bahasa Indonesia.
+// Baris kosong selalu dianggap batas paragraf.
+func SplitSentences(text string) []Segment {
+	runes := []rune(text)
+	segments := []Segment{}
+
+	start := 0
+	emit := func(end int) {
+		if segment, ok := trimSegment(runes, start, end); ok {
+			segments = append(segments, segment)
+		}
+		start = end
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		// Paragraf baru
+		if r == '\n' && nextLineIsBlank(runes, i+1) {
+			emit(i)
+			continue
+		}
+
+		if !isTerminator(r) {
+			continue
+		}
+
+		// Ikutkan tanda baca berulang dan kutip/kurung penutup
+		end := i + 1
+		for end < len(runes) && (isTerminator(runes[end]) || isCloser(runes[end])) {
+			end++
+		}
+		if end < len(runes) && !unicode.IsSpace(runes[end]) {
+			continue // mis. 1.250.000, 08.30, example.com
+		}
+
+		if r == '.' && end == i+1 && !isSentenceEnd(runes, i, end) {
+			continue
+		}
+
+		emit(end)
+		i = end - 1
+	}
+	emit(len(runes))
+
+	return segments
+}
+
+// isSentenceEnd memutuskan apakah titik pada posisi dot mengakhiri kalimat
+func isSentenceEnd(runes []rune, dot, end int) bool {
+	word := strings.ToLower(wordBefore(runes, dot))
+	next := nextWordStart(runes, end)
+
+	// Huruf kecil atau angka setelah titik berarti kalimat berlanjut
+	if next != 0 && (unicode.IsLower(next) || unicode.IsDigit(next)) {
+		return false
+	}
+
+	switch {
+	case word == "":
+		return true
+	case nonTerminalAbbreviations[word]:
+		return false
+	case terminalAbbreviations[word]:
+		return next == 0 || unicode.IsUpper(next)
+	case len([]rune(word)) == 1 && unicode.IsLetter([]rune(word)[0]):
+		return false // Inisial nama, mis. "B. J. Habibie"
+	case strings.Contains(word, "."):
+		// Gelar seperti S.H. atau S.Kom. di akhir kalimat
+		return next == 0 || unicode.IsUpper(next)
+	}
+	return true
+}
+
+// ChunkText memecah teks panjang menjadi potongan berbasis kalimat,
+// masing-masing tidak lebih dari maxRunes
+func ChunkText(text string, maxRunes int) []Segment {
+	if maxRunes <= 0 {
+		maxRunes = defaultChunkRunes
+	}
+	return groupSegments(text, limitSegments(SplitSentences(text), maxRunes), maxRunes)
+}
+
+// chunkText sama dengan ChunkText tapi hanya mengembalikan teksnya
+func chunkText(text string, maxRunes int) []string {
+	chunks := []string{}
+	for _, segment := range ChunkText(text, maxRunes) {
+		chunks = append(chunks, segment.Text)
+	}
+	return chunks
+}
+
+// limitSegments memecah kalimat yang lebih panjang dari maxRunes di koma atau spasi
+func limitSegments(segments []Segment, maxRunes int) []Segment {
+	limited := make([]Segment, 0, len(segments))
+	for _, segment := range segments {
+		rest := []rune(segment.Text)
+		offset := segment.Start
+		for len(rest) > maxRunes {
+			cut := lastBreak(rest[:maxRunes])
+			if part, ok := trimSegment(rest, 0, cut); ok {
+				part.Start += offset
+				part.End += offset
+				limited = append(limited, part)
+			}
+			rest = rest[cut:]
+			offset += cut
+		}
+		if part, ok := trimSegment(rest, 0, len(rest)); ok {
+			part.Start += offset
+			part.End += offset
+			limited = append(limited, part)
+		}
+	}
+	return limited
+}
+
+// groupSegments menggabungkan kalimat berurutan selama totalnya <= maxRunes
+func groupSegments(text string, segments []Segment, maxRunes int) []Segment {
+	runes := []rune(text)
+	grouped := []Segment{}
+	for _, segment := range segments {
+		last := len(grouped) - 1
+		if last >= 0 && segment.End-grouped[last].Start <= maxRunes {
+			grouped[last].End = segment.End
+			grouped[last].Text = string(runes[grouped[last].Start:segment.End])
+			continue
+		}
+		grouped = append(grouped, segment)
+	}
+	return grouped
+}
+
+// lastBreak mencari posisi potong terbaik di dalam window
+func lastBreak(window []rune) int {
+	// Jeda alami: koma, titik koma, titik dua
+	for i := len(window) - 2; i > len(window)/2; i-- {
+		if strings.ContainsRune(",;:", window[i]) && unicode.IsSpace(window[i+1]) {
+			return i + 1
+		}
+	}
+	// Spasi terakhir
+	for i := len(window) - 1; i > 0; i-- {
+		if unicode.IsSpace(window[i]) {
+			return i
+		}
+	}
+	// Kata yang sangat panjang, potong paksa
+	return len(window)
+}
+
+// trimSegment membuat Segment dari runes[start:end] tanpa spasi di tepinya
+func trimSegment(runes []rune, start, end int) (Segment, bool) {
+	for start < end && unicode.IsSpace(runes[start]) {
+		start++
+	}
+	for end > start && unicode.IsSpace(runes[end-1]) {
+		end--
+	}
+	if start >= end {
+		return Segment{}, false
+	}
+	return Segment{Text: string(runes[start:end]), Start: start, End: end}, true
+}
+
+func wordBefore(runes []rune, pos int) string {
+	start := pos
+	for start > 0 && !unicode.IsSpace(runes[start-1]) && !strings.ContainsRune("(\"'“‘", runes[start-1]) {
+		start--
+	}
+	return string(runes[start:pos])
+}
+
+func nextWordStart(runes []rune, pos int) rune {
+	for pos < len(runes) {
+		r := runes[pos]
+		if !unicode.IsSpace(r) && !strings.ContainsRune("(\"'“‘[«", r) {
+			return r
+		}
+		pos++
+	}
+	return 0
+}
+
+func nextLineIsBlank(runes []rune, pos int) bool {
+	for pos < len(runes) {
+		switch runes[pos] {
+		case '\n':
+			return true
+		case ' ', '\t', '\r':
+			pos++
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func isTerminator(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '…'
+}
+
+func isCloser(r rune) bool {
+	return strings.ContainsRune("\"'”’)]»", r)
+}
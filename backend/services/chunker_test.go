@@ -0,0 +1,88 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Saya tinggal di Jl. Merdeka No. 5. Rumahnya biru.", []string{"Saya tinggal di Jl. Merdeka No. 5.", "Rumahnya biru."}},
+		{"Periksa ke dr. Sari besok. Jangan lupa.", []string{"Periksa ke dr. Sari besok.", "Jangan lupa."}},
+		{"Yth. Bpk. Ahmad di Kel. Sukajadi. Terima kasih.", []string{"Yth. Bpk. Ahmad di Kel. Sukajadi.", "Terima kasih."}},
+		{"Harganya Rp 1.250.000 saja. Suhu 36.5 derajat.", []string{"Harganya Rp 1.250.000 saja.", "Suhu 36.5 derajat."}},
+		{"Nilainya 3. dan seterusnya.", []string{"Nilainya 3. dan seterusnya."}},
+		{"Bawa buku, pensil, dll. Besok ujian.", []string{"Bawa buku, pensil, dll.", "Besok ujian."}},
+		{"Bawa buku, pensil, dll. untuk ujian.", []string{"Bawa buku, pensil, dll. untuk ujian."}},
+		{"Presiden B. J. Habibie lahir di Parepare. Beliau insinyur.", []string{"Presiden B. J. Habibie lahir di Parepare.", "Beliau insinyur."}},
+		{"Dosennya Budi, S.Kom. Dia ramah.", []string{"Dosennya Budi, S.Kom.", "Dia ramah."}},
+		{"Jam 08.30 mulai. Buka example.com dulu.", []string{"Jam 08.30 mulai.", "Buka example.com dulu."}},
+		{"Benarkah?! \"Ya.\" Baiklah.", []string{"Benarkah?!", "\"Ya.\"", "Baiklah."}},
+		{"Paragraf satu\n\nParagraf dua", []string{"Paragraf satu", "Paragraf dua"}},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, segment := range SplitSentences(tt.text) {
+			got = append(got, segment.Text)
+			// Offset harus menunjuk ke teks asli
+			if text := string([]rune(tt.text)[segment.Start:segment.End]); text != segment.Text {
+				t.Errorf("SplitSentences(%q): segment %q has offsets of %q", tt.text, segment.Text, text)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SplitSentences(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestChunkText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxRunes int
+		want     []string
+	}{
+		{"groups sentences", "Satu. Dua. Tiga.", 12, []string{"Satu. Dua.", "Tiga."}},
+		{"keeps abbreviations", "Ke Jl. Sudirman. Lalu pulang.", 20, []string{"Ke Jl. Sudirman.", "Lalu pulang."}},
+		{"splits at comma", "pagi, siang, sore, malam", 14, []string{"pagi, siang,", "sore, malam"}},
+		{"splits at space", "satu dua tiga empat", 10, []string{"satu dua", "tiga empat"}},
+	}
+	for _, tt := range tests {
+		chunks := ChunkText(tt.text, tt.maxRunes)
+		got := []string{}
+		for _, chunk := range chunks {
+			got = append(got, chunk.Text)
+			if n := len([]rune(chunk.Text)); n > tt.maxRunes {
+				t.Errorf("%s: chunk %q has %d runes, max %d", tt.name, chunk.Text, n, tt.maxRunes)
+			}
+			if text := string([]rune(tt.text)[chunk.Start:chunk.End]); text != chunk.Text {
+				t.Errorf("%s: chunk %q has offsets of %q", tt.name, chunk.Text, text)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: ChunkText = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChunkTextLongInput(t *testing.T) {
+	text := strings.Repeat("Obat diminum tiga kali sehari sesudah makan. ", 100)
+	chunks := ChunkText(text, defaultChunkRunes)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want the text split", len(chunks))
+	}
+	joined := []string{}
+	for _, chunk := range chunks {
+		if !strings.HasSuffix(chunk.Text, ".") {
+			t.Errorf("chunk does not end at a sentence: %q", chunk.Text)
+		}
+		joined = append(joined, chunk.Text)
+	}
+	if strings.Join(joined, " ") != strings.TrimSpace(text) {
+		t.Error("chunks do not add up to the original text")
+	}
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultCloudBaseURL endpoint Google Cloud Text-to-Speech
+const DefaultCloudBaseURL = "https://texttospeech.googleapis.com/v1"
+
+// errCloudUnavailable menandai error kuota atau jaringan; render dialihkan ke fallback
+var errCloudUnavailable = errors.New("cloud TTS unavailable")
+
+// CloudTTSService implementasi menggunakan Google Cloud TTS (opsional).
+//
+// Audio dirender lewat REST API text:synthesize sebagai LINEAR16 (WAV), sama
+// dengan engine lokal. Jika kuota habis atau jaringan gagal, render dialihkan ke
+// fallback (SystemTTSService). Cloud tidak memutar suara di host, jadi Speak dan
+// kontrol playback juga memakai fallback.
+type CloudTTSService struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	fallback   TTSService
+}
+
+// NewCloudTTSService membuat instance baru CloudTTSService. baseURL kosong
+// berarti DefaultCloudBaseURL; fallback boleh nil.
+func NewCloudTTSService(apiKey, baseURL string, fallback TTSService) *CloudTTSService {
+	if baseURL == "" {
+		baseURL = DefaultCloudBaseURL
+	}
+	return &CloudTTSService{
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		fallback: fallback,
+	}
+}
+
+// cloudSynthesizeRequest body text:synthesize
+type cloudSynthesizeRequest struct {
+	Input struct {
+		Text string `json:"text"`
+	} `json:"input"`
+	Voice struct {
+		LanguageCode string `json:"languageCode"`
+		Name         string `json:"name,omitempty"`
+	} `json:"voice"`
+	AudioConfig struct {
+		AudioEncoding string  `json:"audioEncoding"`
+		SpeakingRate  float64 `json:"speakingRate"`
+		VolumeGainDb  float64 `json:"volumeGainDb"`
+	} `json:"audioConfig"`
+}
+
+// cloudError format error dari Google API
+type cloudError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// Synthesize merender teks lewat cloud, per potongan kalimat seperti engine lokal
+func (c *CloudTTSService) Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error) {
+	config = applyConfigDefaults(config)
+
+	audio, err := synthesizeChunks(ctx, text, config, func(index int, chunk string) ([]byte, error) {
+		return c.synthesizeChunk(ctx, chunk, config)
+	})
+	if err == nil {
+		audio.Engine = "google"
+		return audio, nil
+	}
+	if !errors.Is(err, errCloudUnavailable) || c.fallback == nil || ctx.Err() != nil {
+		return nil, err
+	}
+
+	log.Printf("Cloud TTS: %v, falling back to system TTS", err)
+	audio, fallbackErr := c.fallback.Synthesize(ctx, text, config)
+	if fallbackErr != nil {
+		return nil, fallbackErr
+	}
+	audio.Fallback = true
+	return audio, nil
+}
+
+func (c *CloudTTSService) synthesizeChunk(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	var body cloudSynthesizeRequest
+	body.Input.Text = text
+	body.Voice.LanguageCode = cloudLanguageCode(config)
+	if isCloudVoice(config.Voice) {
+		body.Voice.Name = config.Voice
+	}
+	body.AudioConfig.AudioEncoding = "LINEAR16"
+	body.AudioConfig.SpeakingRate = config.Speed
+	body.AudioConfig.VolumeGainDb = volumeGainDb(config.Volume)
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		AudioContent string `json:"audioContent"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/text:synthesize", payload, &result); err != nil {
+		return nil, err
+	}
+
+	// LINEAR16 dikirim lengkap dengan header WAV
+	audio, err := base64.StdEncoding.DecodeString(result.AudioContent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audio from cloud TTS: %v", err)
+	}
+	if _, _, err := parseWAV(audio); err != nil {
+		return nil, fmt.Errorf("invalid audio from cloud TTS: %v", err)
+	}
+	return audio, nil
+}
+
+// call menjalankan satu request REST dan men-decode response ke out
+func (c *CloudTTSService) call(ctx context.Context, method, path string, payload []byte, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Goog-Api-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errCloudUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errCloudUnavailable, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr cloudError
+		json.Unmarshal(data, &apiErr)
+		message := apiErr.Error.Message
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+
+		// Kuota habis dan gangguan server bisa ditangani fallback,
+		// error lain (mis. voice tidak ada) dikembalikan apa adanya
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 || apiErr.Error.Status == "RESOURCE_EXHAUSTED" {
+			return fmt.Errorf("%w: %d %s", errCloudUnavailable, resp.StatusCode, message)
+		}
+		return fmt.Errorf("cloud TTS error %d: %s", resp.StatusCode, message)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid response from cloud TTS: %v", err)
+	}
+	return nil
+}
+
+// Speak memutar di host lewat fallback, karena cloud hanya merender audio
+func (c *CloudTTSService) Speak(text string, config TTSConfig) (*TTSResponse, error) {
+	if c.fallback == nil {
+		return nil, ErrNotSupported
+	}
+	return c.fallback.Speak(text, config)
+}
+
+// Stop menghentikan speech di fallback
+func (c *CloudTTSService) Stop() error {
+	if c.fallback == nil {
+		return nil
+	}
+	return c.fallback.Stop()
+}
+
+// Pause menjeda speech di fallback
+func (c *CloudTTSService) Pause() error {
+	if c.fallback == nil {
+		return ErrNotSupported
+	}
+	return c.fallback.Pause()
+}
+
+// Resume melanjutkan speech di fallback
+func (c *CloudTTSService) Resume() error {
+	if c.fallback == nil {
+		return ErrNotSupported
+	}
+	return c.fallback.Resume()
+}
+
+// Status melaporkan speech di fallback
+func (c *CloudTTSService) Status() TTSStatus {
+	if c.fallback == nil {
+		return TTSStatus{}
+	}
+	return c.fallback.Status()
+}
+
+// GetVoices mendapatkan daftar voice dari voices:list
+func (c *CloudTTSService) GetVoices() ([]string, error) {
+	var result struct {
+		Voices []struct {
+			Name          string   `json:"name"`
+			LanguageCodes []string `json:"languageCodes"`
+		} `json:"voices"`
+	}
+	if err := c.call(context.Background(), http.MethodGet, "/voices", nil, &result); err != nil {
+		return nil, err
+	}
+
+	voices := []string{}
+	for _, voice := range result.Voices {
+		voices = append(voices, voice.Name)
+	}
+	return voices, nil
+}
+
+// IsSpeaking mengecek apakah sedang ada speech yang berjalan di fallback
+func (c *CloudTTSService) IsSpeaking() bool {
+	return c.fallback != nil && c.fallback.IsSpeaking()
+}
+
+// Unwrap mengembalikan fallback, supaya navigasi kalimat tetap bisa dipakai
+func (c *CloudTTSService) Unwrap() TTSService {
+	return c.fallback
+}
+
+// cloudLanguageCode melengkapi kode bahasa (id -> id-ID). Jika voice cloud
+// diminta, kode bahasanya diambil dari nama voice (id-ID-Standard-A).
+func cloudLanguageCode(config TTSConfig) string {
+	if isCloudVoice(config.Voice) {
+		parts := strings.SplitN(config.Voice, "-", 3)
+		return parts[0] + "-" + parts[1]
+	}
+	switch strings.ToLower(config.Language) {
+	case "id":
+		return "id-ID"
+	case "en":
+		return "en-US"
+	}
+	return config.Language
+}
+
+// isCloudVoice mengenali nama voice Google (<bahasa>-<REGION>-<jenis>-<huruf>)
+func isCloudVoice(voice string) bool {
+	return strings.Count(voice, "-") >= 3
+}
+
+// volumeGainDb mengubah volume 0.0 - 1.0 menjadi gain dB (-96 sampai 0)
+func volumeGainDb(volume float64) float64 {
+	if volume <= 0 {
+		return -96
+	}
+	if volume >= 1 {
+		return 0
+	}
+	return math.Max(-96, 20*math.Log10(volume))
+}
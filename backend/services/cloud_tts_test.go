@@ -0,0 +1,234 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeCloud server Google Cloud TTS palsu; handler menjawab setiap request
+// dan request yang diterima dicatat
+type fakeCloud struct {
+	mu       sync.Mutex
+	requests []cloudSynthesizeRequest
+	paths    []string
+	keys     []string
+}
+
+func newFakeCloud(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) (*httptest.Server, *fakeCloud) {
+	t.Helper()
+	fake := &fakeCloud{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var request cloudSynthesizeRequest
+		json.Unmarshal(data, &request)
+
+		fake.mu.Lock()
+		fake.paths = append(fake.paths, r.Method+" "+r.URL.Path)
+		fake.keys = append(fake.keys, r.Header.Get("X-Goog-Api-Key"))
+		if r.Method == http.MethodPost {
+			fake.requests = append(fake.requests, request)
+		}
+		fake.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, fake
+}
+
+// cloudAudioHandler menjawab text:synthesize dengan LINEAR16 base64
+func cloudAudioHandler(wav []byte) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"audioContent": base64.StdEncoding.EncodeToString(wav),
+		})
+	}
+}
+
+// cloudErrorHandler menjawab dengan error format Google API
+func cloudErrorHandler(status int, apiStatus, message string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		var body cloudError
+		body.Error.Code = status
+		body.Error.Status = apiStatus
+		body.Error.Message = message
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+func newBuiltinFallback() *SystemTTSService {
+	return NewSystemTTSService(NewEngineRegistry(NewBuiltinEngine()))
+}
+
+func TestCloudSynthesize(t *testing.T) {
+	wav := testWAV(24000, 2400)
+	server, fake := newFakeCloud(t, cloudAudioHandler(wav))
+	cloud := NewCloudTTSService("secret", server.URL+"/", newBuiltinFallback())
+
+	audio, err := cloud.Synthesize(context.Background(), "Selamat pagi, Ibu.", TTSConfig{Language: "id", Speed: 1.25, Volume: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if audio.Engine != "google" || audio.Fallback || audio.ContentType != "audio/wav" {
+		t.Errorf("result = engine %q, fallback %v, content type %q", audio.Engine, audio.Fallback, audio.ContentType)
+	}
+	format, pcm, err := parseWAV(audio.Data)
+	if err != nil {
+		t.Fatalf("result is not a WAV: %v", err)
+	}
+	if format.AudioFormat != 1 || format.Channels != 1 || format.SampleRate != 24000 || format.BitsPerSample != 16 {
+		t.Errorf("format = %+v, want 24 kHz mono 16-bit PCM", format)
+	}
+	if _, want, _ := parseWAV(wav); !bytes.Equal(pcm, want) {
+		t.Errorf("PCM = %d bytes, want the %d bytes from the cloud", len(pcm), len(want))
+	}
+	if audio.AudioDuration != 100 {
+		t.Errorf("audio duration = %v ms, want 100", audio.AudioDuration)
+	}
+	if len(audio.Marks) == 0 {
+		t.Error("no marks for cloud audio")
+	}
+
+	if len(fake.paths) != 1 || fake.paths[0] != "POST /text:synthesize" || fake.keys[0] != "secret" {
+		t.Fatalf("requests = %v with keys %v", fake.paths, fake.keys)
+	}
+	request := fake.requests[0]
+	if request.Input.Text != "Selamat pagi, Ibu." {
+		t.Errorf("text = %q", request.Input.Text)
+	}
+	if request.Voice.LanguageCode != "id-ID" || request.Voice.Name != "" {
+		t.Errorf("voice = %+v, want id-ID without a name", request.Voice)
+	}
+	if config := request.AudioConfig; config.AudioEncoding != "LINEAR16" || config.SpeakingRate != 1.25 || config.VolumeGainDb > -6 || config.VolumeGainDb < -6.1 {
+		t.Errorf("audio config = %+v", config)
+	}
+}
+
+func TestCloudSynthesizeVoiceName(t *testing.T) {
+	server, fake := newFakeCloud(t, cloudAudioHandler(testWAV(24000, 240)))
+	cloud := NewCloudTTSService("secret", server.URL, nil)
+
+	if _, err := cloud.Synthesize(context.Background(), "Good morning.", TTSConfig{Language: "id-ID", Voice: "en-GB-Standard-A"}); err != nil {
+		t.Fatal(err)
+	}
+	if voice := fake.requests[0].Voice; voice.Name != "en-GB-Standard-A" || voice.LanguageCode != "en-GB" {
+		t.Errorf("voice = %+v, want the cloud voice and its language", voice)
+	}
+}
+
+func TestCloudGetVoices(t *testing.T) {
+	server, fake := newFakeCloud(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"voices":[
+			{"name":"id-ID-Standard-A","languageCodes":["id-ID"],"ssmlGender":"FEMALE"},
+			{"name":"id-ID-Wavenet-B","languageCodes":["id-ID"],"ssmlGender":"MALE"}
+		]}`)
+	})
+	cloud := NewCloudTTSService("secret", server.URL, nil)
+
+	voices, err := cloud.GetVoices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(voices) != 2 || voices[0] != "id-ID-Standard-A" || voices[1] != "id-ID-Wavenet-B" {
+		t.Errorf("voices = %v", voices)
+	}
+	if fake.paths[0] != "GET /voices" {
+		t.Errorf("request = %q, want GET /voices", fake.paths[0])
+	}
+}
+
+func TestCloudFallsBackWhenUnavailable(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}{
+		{"rate limited", cloudErrorHandler(http.StatusTooManyRequests, "RESOURCE_EXHAUSTED", "Quota exceeded")},
+		{"server error", cloudErrorHandler(http.StatusInternalServerError, "INTERNAL", "Internal error")},
+		{"unavailable without body", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) }},
+		{"quota as 403", cloudErrorHandler(http.StatusForbidden, "RESOURCE_EXHAUSTED", "Daily limit reached")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newFakeCloud(t, tt.handler)
+			cloud := NewCloudTTSService("secret", server.URL, newBuiltinFallback())
+
+			audio, err := cloud.Synthesize(context.Background(), "Obat diminum pagi hari.", TTSConfig{Language: "id-ID"})
+			if err != nil {
+				t.Fatalf("Synthesize() = %v, want fallback audio", err)
+			}
+			if !audio.Fallback || audio.Engine != "builtin" {
+				t.Errorf("result = engine %q, fallback %v, want builtin fallback", audio.Engine, audio.Fallback)
+			}
+			if _, _, err := parseWAV(audio.Data); err != nil {
+				t.Errorf("fallback audio is not a WAV: %v", err)
+			}
+		})
+	}
+}
+
+func TestCloudFallsBackOnNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	target := server.URL
+	server.Close()
+
+	cloud := NewCloudTTSService("secret", target, newBuiltinFallback())
+	audio, err := cloud.Synthesize(context.Background(), "Halo.", TTSConfig{Language: "id-ID"})
+	if err != nil {
+		t.Fatalf("Synthesize() = %v, want fallback audio", err)
+	}
+	if !audio.Fallback {
+		t.Error("network error did not fall back")
+	}
+
+	// Tanpa fallback error jaringan dikembalikan
+	cloud = NewCloudTTSService("secret", target, nil)
+	if _, err := cloud.Synthesize(context.Background(), "Halo.", TTSConfig{Language: "id-ID"}); err == nil || !strings.Contains(err.Error(), "cloud TTS unavailable") {
+		t.Errorf("error = %v, want cloud TTS unavailable", err)
+	}
+}
+
+func TestCloudReturnsClientErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+		want    string
+	}{
+		{"bad voice", cloudErrorHandler(http.StatusBadRequest, "INVALID_ARGUMENT", "Voice 'x' does not exist."), "cloud TTS error 400: Voice 'x' does not exist."},
+		{"bad key", cloudErrorHandler(http.StatusForbidden, "PERMISSION_DENIED", "API key not valid."), "cloud TTS error 403: API key not valid."},
+		{"not found without body", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) }, "cloud TTS error 404: Not Found"},
+		{"bad audio", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, `{"audioContent":"bm90IGEgd2F2"}`) }, "invalid audio from cloud TTS"},
+		{"bad json", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, `{`) }, "invalid response from cloud TTS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newFakeCloud(t, tt.handler)
+			cloud := NewCloudTTSService("secret", server.URL, newBuiltinFallback())
+
+			audio, err := cloud.Synthesize(context.Background(), "Halo.", TTSConfig{Language: "id-ID"})
+			if err == nil {
+				t.Fatalf("Synthesize() = engine %q, want an error instead of fallback", audio.Engine)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	// GetVoices tidak punya fallback, error kuota juga dikembalikan
+	server, _ := newFakeCloud(t, cloudErrorHandler(http.StatusTooManyRequests, "RESOURCE_EXHAUSTED", "Quota exceeded"))
+	cloud := NewCloudTTSService("secret", server.URL, newBuiltinFallback())
+	if _, err := cloud.GetVoices(); err == nil || !strings.Contains(err.Error(), "429 Quota exceeded") {
+		t.Errorf("GetVoices() error = %v", err)
+	}
+}
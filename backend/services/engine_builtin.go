@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"strings"
+)
+
+// BuiltinEngine synthesizer formant sederhana di dalam binary, sebagai engine
+// terakhir jika tidak ada engine lain yang terpasang (mis. image Docker Alpine).
+// Teks dibaca dengan aturan ejaan Indonesia; suaranya robotik tapi jelas.
+type BuiltinEngine struct{}
+
+func NewBuiltinEngine() *BuiltinEngine {
+	return &BuiltinEngine{}
+}
+
+func (e *BuiltinEngine) Name() string {
+	return "builtin"
+}
+
+func (e *BuiltinEngine) Capabilities() EngineCapabilities {
+	return EngineCapabilities{
+		Languages: []string{"id"},
+		Formats:   []string{"wav"},
+		Marks:     true,
+	}
+}
+
+// Available selalu true karena tidak butuh program lain
+func (e *BuiltinEngine) Available() bool {
+	return true
+}
+
+// Render mensintesis WAV 16 kHz langsung di Go
+func (e *BuiltinEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrNotSupported
+	}
+	return synthesizeFormant(text, config.Speed, config.Volume), nil
+}
+
+func (e *BuiltinEngine) Voices() ([]Voice, error) {
+	return []Voice{{Name: "builtin-id", Language: "id-ID", Engine: e.Name()}}, nil
+}
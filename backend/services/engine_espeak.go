@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+// EspeakEngine engine espeak (Linux). Jika EnginePool diberikan, render dan
+// pemutaran di speaker memakai worker yang hidup terus, dan kembali ke satu
+// proses espeak per request jika pool sibuk atau gagal.
+type EspeakEngine struct {
+	pool   *EnginePool
+	player []string // Pemutar WAV dari stdin untuk audio hasil pool (aplay/paplay)
+}
+
+// NewEspeakEngine membuat engine espeak; pool boleh nil
+func NewEspeakEngine(pool *EnginePool) *EspeakEngine {
+	e := &EspeakEngine{pool: pool}
+	if pool != nil {
+		for _, player := range [][]string{{"aplay", "-q"}, {"paplay"}} {
+			if path, err := exec.LookPath(player[0]); err == nil {
+				e.player = append([]string{path}, player[1:]...)
+				break
+			}
+		}
+		if e.player == nil {
+			log.Printf("Engine pool: aplay/paplay not found, speaker playback starts espeak per sentence")
+		}
+	}
+	return e
+}
+
+func (e *EspeakEngine) Name() string {
+	return "espeak"
+}
+
+func (e *EspeakEngine) Capabilities() EngineCapabilities {
+	return EngineCapabilities{
+		Languages: []string{"id", "en"},
+		Formats:   []string{"wav"},
+		Marks:     true,
+	}
+}
+
+func (e *EspeakEngine) Available() bool {
+	_, err := exec.LookPath("espeak")
+	return err == nil
+}
+
+// SpeakCommand memutar teks langsung ke speaker. Dengan pool, audio dirender
+// worker lalu diputar pemutar WAV, sehingga espeak tidak di-fork per kalimat.
+func (e *EspeakEngine) SpeakCommand(ctx context.Context, text string, config TTSConfig) (*exec.Cmd, error) {
+	if e.pool != nil && e.player != nil {
+		audio, err := e.pool.Render(text, config)
+		if err == nil {
+			cmd := exec.CommandContext(ctx, e.player[0], e.player[1:]...)
+			cmd.Stdin = bytes.NewReader(audio)
+			return cmd, nil
+		}
+		if !errors.Is(err, ErrPoolBusy) {
+			log.Printf("Engine pool: falling back to a new process: %v", err)
+		}
+	}
+	return exec.CommandContext(ctx, "espeak", espeakArgs(text, config, "")...), nil
+}
+
+// Render merender lewat worker pool, atau satu proses espeak -w
+func (e *EspeakEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	if e.pool != nil {
+		audio, err := e.pool.Render(text, config)
+		if err == nil {
+			return audio, nil
+		}
+		if !errors.Is(err, ErrPoolBusy) {
+			log.Printf("Engine pool: falling back to a new process: %v", err)
+		}
+	}
+	return renderToFile(func(outFile string) *exec.Cmd {
+		return exec.CommandContext(ctx, "espeak", espeakArgs(text, config, outFile)...)
+	})
+}
+
+// Voices membaca daftar voice dari espeak --voices
+func (e *EspeakEngine) Voices() ([]Voice, error) {
+	output, err := exec.Command("espeak", "--voices").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	voices := []Voice{}
+	lines := strings.Split(string(output), "\n")
+	for i, line := range lines {
+		if i == 0 {
+			continue // Skip header
+		}
+		parts := strings.Fields(line)
+		if len(parts) > 1 {
+			// Kode bahasa juga dipakai sebagai nama voice (-v)
+			voices = append(voices, Voice{Name: parts[1], Language: parts[1], Engine: e.Name()})
+		}
+	}
+	return voices, nil
+}
+
+// espeakArgs menyusun argumen espeak. Jika outFile diisi, WAV ditulis ke file
+// alih-alih diputar.
+func espeakArgs(text string, config TTSConfig, outFile string) []string {
+	args := []string{"-v", espeakVoice(config)}
+
+	// Speed (espeak default 175, range 80-450)
+	speed := int(175 * config.Speed)
+	if speed < 80 {
+		speed = 80
+	}
+	if speed > 450 {
+		speed = 450
+	}
+	args = append(args, "-s", fmt.Sprintf("%d", speed))
+
+	// Volume (0-200, default 100)
+	volume := int(config.Volume * 100)
+	args = append(args, "-a", fmt.Sprintf("%d", volume))
+
+	// Pitch (30-99, default 50)
+	pitch := 50
+	args = append(args, "-p", fmt.Sprintf("%d", pitch))
+
+	if outFile != "" {
+		args = append(args, "-w", outFile)
+	}
+	return append(args, text)
+}
+
+// espeakVoice memilih voice espeak berdasarkan bahasa, kecuali voice diminta langsung
+func espeakVoice(config TTSConfig) string {
+	if config.Voice != "" {
+		return config.Voice
+	}
+	// Dicocokkan per bahasa dasar, supaya en-GB atau en-AU tidak jatuh ke voice id
+	switch strings.ToLower(config.Language) {
+	case "en-gb":
+		return "en"
+	}
+	if strings.EqualFold(strings.SplitN(config.Language, "-", 2)[0], "en") {
+		return "en-us"
+	}
+	return "id" // Indonesian
+}
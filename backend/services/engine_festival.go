@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// festivalVoiceName nama voice yang aman disisipkan ke script Scheme
+var festivalVoiceName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// FestivalEngine engine festival (Linux). Perintah dikirim sebagai script
+// Scheme ke `festival --pipe` lewat stdin, tanpa shell.
+type FestivalEngine struct{}
+
+func NewFestivalEngine() *FestivalEngine {
+	return &FestivalEngine{}
+}
+
+func (e *FestivalEngine) Name() string {
+	return "festival"
+}
+
+func (e *FestivalEngine) Capabilities() EngineCapabilities {
+	return EngineCapabilities{
+		Languages: []string{"en"},
+		Formats:   []string{"wav"},
+		Marks:     true,
+	}
+}
+
+func (e *FestivalEngine) Available() bool {
+	_, err := exec.LookPath("festival")
+	return err == nil
+}
+
+// SpeakCommand memutar teks langsung ke speaker
+func (e *FestivalEngine) SpeakCommand(ctx context.Context, text string, config TTSConfig) (*exec.Cmd, error) {
+	setup, err := festivalSetup(config)
+	if err != nil {
+		return nil, err
+	}
+	script := setup + fmt.Sprintf("(SayText %s)\n", schemeString(text))
+
+	cmd := exec.CommandContext(ctx, "festival", "--pipe")
+	cmd.Stdin = strings.NewReader(script)
+	return cmd, nil
+}
+
+// Render mensintesis utterance lalu menyimpannya sebagai WAV (tanpa diputar)
+func (e *FestivalEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	setup, err := festivalSetup(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderToFile(func(outFile string) *exec.Cmd {
+		script := setup + fmt.Sprintf(
+			"(set! utt (utt.synth (Utterance Text %s)))\n(utt.save.wave utt %s 'riff)\n",
+			schemeString(text), schemeString(outFile))
+
+		cmd := exec.CommandContext(ctx, "festival", "--pipe")
+		cmd.Stdin = strings.NewReader(script)
+		return cmd
+	})
+}
+
+// Voices membaca daftar voice dari (voice.list)
+func (e *FestivalEngine) Voices() ([]Voice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "festival", "--pipe")
+	cmd.Stdin = strings.NewReader("(print (voice.list))\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	// Output berbentuk list Scheme, mis. (kal_diphone rab_diphone)
+	voices := []Voice{}
+	list := strings.NewReplacer("(", " ", ")", " ").Replace(string(output))
+	for _, name := range strings.Fields(list) {
+		if festivalVoiceName.MatchString(name) {
+			voices = append(voices, Voice{Name: name, Engine: e.Name()})
+		}
+	}
+	return voices, nil
+}
+
+// festivalSetup membuat bagian awal script: voice, speed dan volume dari config
+func festivalSetup(config TTSConfig) (string, error) {
+	var script strings.Builder
+	if config.Voice != "" {
+		if !festivalVoiceName.MatchString(config.Voice) {
+			return "", fmt.Errorf("invalid festival voice %q", config.Voice)
+		}
+		fmt.Fprintf(&script, "(voice_%s)\n", config.Voice)
+	}
+
+	// Duration_Stretch adalah kebalikan kecepatan: 2.0 = dua kali lebih lambat
+	speed := config.Speed
+	if speed <= 0 {
+		speed = 1.0
+	}
+	fmt.Fprintf(&script, "(Parameter.set 'Duration_Stretch %.2f)\n", 1.0/speed)
+
+	// Volume diterapkan ke gelombang setiap utterance setelah sintesis
+	if config.Volume >= 0 && config.Volume != 1.0 {
+		fmt.Fprintf(&script, "(set! after_synth_hooks (list (lambda (utt) (utt.wave.rescale utt %.2f))))\n", config.Volume)
+	}
+	return script.String(), nil
+}
+
+// schemeString menulis s sebagai string literal Scheme
+func schemeString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
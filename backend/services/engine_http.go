@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaryTTSPreset konfigurasi HTTPEngine untuk server MaryTTS (port bawaan 59125).
+// MaryTTS bawaan tidak punya voice Indonesia, jadi hanya bahasa yang ada
+// voice-nya yang diklaim; request bahasa Indonesia tidak membuka circuit breaker.
+var MaryTTSPreset = HTTPEngineConfig{
+	Name:      "marytts",
+	URL:       "http://localhost:59125/process",
+	Method:    http.MethodGet,
+	Params:    "INPUT_TEXT={text}&INPUT_TYPE=TEXT&OUTPUT_TYPE=AUDIO&AUDIO=WAVE_FILE&LOCALE={locale}&VOICE={voice}",
+	Format:    "wav",
+	VoicesURL: "http://localhost:59125/voices",
+	Languages: []string{"en", "de", "fr", "it", "te", "tr", "ru", "sv", "lb"},
+}
+
+// HTTPEngineConfig cara memanggil server TTS lewat HTTP.
+//
+// URL dan Params boleh berisi placeholder yang diisi dari TTSConfig: {text},
+// {language} (id), {locale} (id_ID), {voice}, {speed} (1.00), {rate} (persen,
+// 100) dan {volume} (0.00-1.00). Params ditulis seperti query string; untuk
+// GET ditambahkan ke URL, untuk POST dikirim sebagai form. Parameter yang
+// hanya berisi satu placeholder dan nilainya kosong tidak dikirim.
+//
+// Format menyatakan isi response: "wav" (audio WAV mentah), "base64" (WAV
+// dalam base64) atau "json:<field>" (WAV base64 di field JSON, boleh bertitik,
+// mis. json:data.audio).
+type HTTPEngineConfig struct {
+	Name      string
+	URL       string
+	Method    string
+	Params    string
+	Format    string
+	Languages []string // Kosong berarti semua bahasa
+	VoicesURL string   // Opsional: daftar voice, satu per baris "nama locale ..." atau JSON array
+	Timeout   time.Duration
+}
+
+// HTTPEngine engine yang merender lewat server TTS di jaringan (MaryTTS atau
+// server lain dengan API HTTP sederhana)
+type HTTPEngine struct {
+	config     HTTPEngineConfig
+	httpClient *http.Client
+}
+
+// NewHTTPEngine membuat engine HTTP. Nama kosong menjadi "http", method
+// kosong menjadi GET dan format kosong menjadi wav.
+func NewHTTPEngine(config HTTPEngineConfig) (*HTTPEngine, error) {
+	if config.Name == "" {
+		config.Name = "http"
+	}
+	if config.Method == "" {
+		config.Method = http.MethodGet
+	}
+	config.Method = strings.ToUpper(config.Method)
+	if config.Method != http.MethodGet && config.Method != http.MethodPost {
+		return nil, fmt.Errorf("unsupported HTTP method %q", config.Method)
+	}
+	if config.Format == "" {
+		config.Format = "wav"
+	}
+	if config.Format != "wav" && config.Format != "base64" && !strings.HasPrefix(config.Format, "json:") {
+		return nil, fmt.Errorf("unsupported response format %q", config.Format)
+	}
+	// Disalin supaya slice milik preset tidak ikut diubah
+	var languages []string
+	for _, language := range config.Languages {
+		languages = append(languages, strings.ToLower(strings.TrimSpace(language)))
+	}
+	config.Languages = languages
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	// Template dicek sekali di awal dengan nilai contoh
+	if _, err := url.Parse(expandHTTPTemplate(config.URL, TTSConfig{}, "", url.PathEscape)); err != nil {
+		return nil, fmt.Errorf("invalid URL template: %v", err)
+	}
+	if _, err := url.ParseQuery(config.Params); err != nil {
+		return nil, fmt.Errorf("invalid params template: %v", err)
+	}
+
+	return &HTTPEngine{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}, nil
+}
+
+func (e *HTTPEngine) Name() string {
+	return e.config.Name
+}
+
+func (e *HTTPEngine) Capabilities() EngineCapabilities {
+	return EngineCapabilities{
+		Languages: e.config.Languages,
+		Formats:   []string{"wav"},
+		Marks:     true,
+	}
+}
+
+// Available jika URL diatur; server yang mati ditangani circuit breaker
+func (e *HTTPEngine) Available() bool {
+	return e.config.URL != ""
+}
+
+// Render mengirim satu request ke server dan mengambil WAV dari response
+func (e *HTTPEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	req, err := e.buildRequest(ctx, text, config)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := e.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	audio, err := e.decodeAudio(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audio from %s: %v", e.config.Name, err)
+	}
+	if _, _, err := parseWAV(audio); err != nil {
+		return nil, fmt.Errorf("invalid audio from %s: %v", e.config.Name, err)
+	}
+	return audio, nil
+}
+
+// buildRequest mengisi template URL dan parameter dari config
+func (e *HTTPEngine) buildRequest(ctx context.Context, text string, config TTSConfig) (*http.Request, error) {
+	// %20 untuk spasi, supaya benar baik di path maupun query
+	target := expandHTTPTemplate(e.config.URL, config, text, func(value string) string {
+		return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+	})
+
+	params := url.Values{}
+	if e.config.Params != "" {
+		for _, pair := range strings.Split(e.config.Params, "&") {
+			key, value, _ := strings.Cut(pair, "=")
+			key, _ = url.QueryUnescape(key)
+			value, _ = url.QueryUnescape(value)
+			expanded := expandHTTPTemplate(value, config, text, nil)
+			if expanded == "" && isHTTPPlaceholder(value) {
+				continue
+			}
+			params.Add(key, expanded)
+		}
+	}
+
+	var body io.Reader
+	if e.config.Method == http.MethodGet {
+		if len(params) > 0 {
+			separator := "?"
+			if strings.Contains(target, "?") {
+				separator = "&"
+			}
+			target += separator + params.Encode()
+		}
+	} else {
+		body = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, e.config.Method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return req, nil
+}
+
+// do menjalankan request dan mengembalikan body jika status 2xx
+func (e *HTTPEngine) do(req *http.Request) ([]byte, error) {
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", e.config.Name, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", e.config.Name, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		message := strings.TrimSpace(string(data))
+		if len(message) > 200 {
+			message = message[:200]
+		}
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("%s error %d: %s", e.config.Name, resp.StatusCode, message)
+	}
+	return data, nil
+}
+
+// decodeAudio mengambil WAV dari body sesuai Format
+func (e *HTTPEngine) decodeAudio(data []byte) ([]byte, error) {
+	switch {
+	case e.config.Format == "wav":
+		return data, nil
+	case e.config.Format == "base64":
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	}
+
+	path := strings.TrimPrefix(e.config.Format, "json:")
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	for _, field := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field %q not found", path)
+		}
+		value = object[field]
+	}
+	encoded, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q not found", path)
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
+// Voices membaca VoicesURL jika diatur. Format baris MaryTTS
+// ("cmu-slt-hsmm en_US female hmm") dan JSON array nama voice didukung.
+func (e *HTTPEngine) Voices() ([]Voice, error) {
+	if e.config.VoicesURL == "" {
+		return []Voice{}, nil
+	}
+	// Daftar voice diminta di /api/voices, jangan menunggu server yang mati terlalu lama
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.config.VoicesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := e.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	voices := []Voice{}
+	var names []string
+	if json.Unmarshal(data, &names) == nil {
+		for _, name := range names {
+			voices = append(voices, Voice{Name: name, Engine: e.config.Name})
+		}
+		return voices, nil
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		voice := Voice{Name: fields[0], Engine: e.config.Name}
+		if len(fields) > 1 {
+			voice.Language = strings.ReplaceAll(fields[1], "_", "-")
+		}
+		voices = append(voices, voice)
+	}
+	return voices, nil
+}
+
+// expandHTTPTemplate mengganti placeholder dengan nilai dari config. escape
+// dipakai untuk nilai yang masuk ke URL; nil berarti nilai dipakai apa adanya.
+func expandHTTPTemplate(template string, config TTSConfig, text string, escape func(string) string) string {
+	if escape == nil {
+		escape = func(value string) string { return value }
+	}
+
+	// Locale dilengkapi dari bahasa (id -> id_ID) kecuali wilayahnya sudah disebut
+	language := strings.ToLower(strings.SplitN(config.Language, "-", 2)[0])
+	locale := strings.Replace(cloudLanguageCode(TTSConfig{Language: config.Language}), "-", "_", 1)
+
+	replacer := strings.NewReplacer(
+		"{text}", escape(text),
+		"{language}", escape(language),
+		"{locale}", escape(locale),
+		"{voice}", escape(config.Voice),
+		"{speed}", fmt.Sprintf("%.2f", config.Speed),
+		"{rate}", fmt.Sprintf("%d", int(config.Speed*100+0.5)),
+		"{volume}", fmt.Sprintf("%.2f", config.Volume),
+	)
+	return replacer.Replace(template)
+}
+
+// isHTTPPlaceholder mengecek apakah nilai parameter hanya satu placeholder
+func isHTTPPlaceholder(value string) bool {
+	return strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") && strings.Count(value, "{") == 1
+}
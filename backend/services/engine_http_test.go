@@ -0,0 +1,411 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// testWAV WAV mono 16-bit kecil untuk respons server palsu
+func testWAV(sampleRate uint32, samples int) []byte {
+	format := wavFormat{
+		AudioFormat:   1,
+		Channels:      1,
+		SampleRate:    sampleRate,
+		ByteRate:      sampleRate * 2,
+		BlockAlign:    2,
+		BitsPerSample: 16,
+	}
+	pcm := make([]byte, samples*2)
+	for i := range pcm {
+		pcm[i] = byte(i)
+	}
+	return encodeWAV(format, pcm)
+}
+
+// capturedRequest request terakhir yang diterima server palsu
+type capturedRequest struct {
+	method      string
+	path        string
+	rawPath     string
+	query       url.Values
+	form        url.Values
+	contentType string
+}
+
+// newHTTPEngineServer server palsu yang mencatat request dan membalas body
+// dengan status tertentu
+func newHTTPEngineServer(t *testing.T, status int, body []byte) (*httptest.Server, *capturedRequest) {
+	t.Helper()
+	captured := &capturedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		captured.method = r.Method
+		captured.path = r.URL.Path
+		captured.rawPath = r.URL.EscapedPath()
+		captured.query = r.URL.Query()
+		captured.form, _ = url.ParseQuery(string(data))
+		captured.contentType = r.Header.Get("Content-Type")
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, captured
+}
+
+func TestHTTPEngineExpandsPlaceholders(t *testing.T) {
+	wav := testWAV(22050, 100)
+	server, captured := newHTTPEngineServer(t, http.StatusOK, wav)
+
+	engine, err := NewHTTPEngine(HTTPEngineConfig{
+		URL:    server.URL + "/say/{language}/{text}",
+		Params: "locale={locale}&voice={voice}&speed={speed}&rate={rate}&volume={volume}&note=v-{voice}&fixed=1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := TTSConfig{Language: "id-ID", Speed: 1.25, Volume: 0.5}
+	audio, err := engine.Render(context.Background(), "halo dunia & kamu", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(audio, wav) {
+		t.Errorf("audio = %d bytes, want the WAV from the server", len(audio))
+	}
+
+	if captured.path != "/say/id/halo dunia & kamu" {
+		t.Errorf("path = %q", captured.path)
+	}
+	if !strings.Contains(captured.rawPath, "halo%20dunia%20%26%20kamu") {
+		t.Errorf("raw path = %q, want spaces as %%20", captured.rawPath)
+	}
+
+	want := map[string]string{
+		"locale": "id_ID",
+		"speed":  "1.25",
+		"rate":   "125",
+		"volume": "0.50",
+		"note":   "v-",
+		"fixed":  "1",
+	}
+	for key, value := range want {
+		if got := captured.query.Get(key); got != value {
+			t.Errorf("query %s = %q, want %q", key, got, value)
+		}
+	}
+	// Parameter yang hanya berisi satu placeholder kosong tidak dikirim
+	if _, ok := captured.query["voice"]; ok {
+		t.Errorf("empty voice parameter was sent: %v", captured.query)
+	}
+}
+
+func TestHTTPEngineLocaleKeepsRegion(t *testing.T) {
+	server, captured := newHTTPEngineServer(t, http.StatusOK, testWAV(16000, 10))
+	engine, err := NewHTTPEngine(HTTPEngineConfig{URL: server.URL, Params: "l={locale}&v={voice}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Render(context.Background(), "hello", TTSConfig{Language: "en-GB", Voice: "cmu-slt"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := captured.query.Get("l"); got != "en_GB" {
+		t.Errorf("locale = %q, want en_GB", got)
+	}
+	if got := captured.query.Get("v"); got != "cmu-slt" {
+		t.Errorf("voice = %q, want cmu-slt", got)
+	}
+}
+
+func TestHTTPEngineMethods(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+	}{
+		{http.MethodGet, "/tts?key=abc"},
+		{http.MethodPost, "/tts?key=abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			server, captured := newHTTPEngineServer(t, http.StatusOK, testWAV(16000, 10))
+			engine, err := NewHTTPEngine(HTTPEngineConfig{
+				URL:    server.URL + tt.url,
+				Method: strings.ToLower(tt.method),
+				Params: "text={text}",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := engine.Render(context.Background(), "selamat pagi", TTSConfig{Language: "id-ID"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if captured.method != tt.method {
+				t.Errorf("method = %s, want %s", captured.method, tt.method)
+			}
+			if captured.query.Get("key") != "abc" {
+				t.Errorf("query from the URL template was lost: %v", captured.query)
+			}
+			switch tt.method {
+			case http.MethodGet:
+				if captured.query.Get("text") != "selamat pagi" {
+					t.Errorf("GET query = %v, want text in the query", captured.query)
+				}
+				if len(captured.form) != 0 {
+					t.Errorf("GET sent a body: %v", captured.form)
+				}
+			case http.MethodPost:
+				if captured.form.Get("text") != "selamat pagi" {
+					t.Errorf("POST form = %v, want text in the body", captured.form)
+				}
+				if captured.query.Get("text") != "" {
+					t.Errorf("POST put params in the query: %v", captured.query)
+				}
+				if captured.contentType != "application/x-www-form-urlencoded" {
+					t.Errorf("content type = %q", captured.contentType)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPEngineFormats(t *testing.T) {
+	wav := testWAV(16000, 50)
+	encoded := base64.StdEncoding.EncodeToString(wav)
+	tests := []struct {
+		name   string
+		format string
+		body   string
+	}{
+		{"wav", "", string(wav)},
+		{"base64", "base64", encoded + "\n"},
+		{"json", "json:audio", `{"audio":"` + encoded + `"}`},
+		{"json path", "json:data.audio", `{"data":{"audio":"` + encoded + `","rate":16000}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newHTTPEngineServer(t, http.StatusOK, []byte(tt.body))
+			engine, err := NewHTTPEngine(HTTPEngineConfig{URL: server.URL, Format: tt.format})
+			if err != nil {
+				t.Fatal(err)
+			}
+			audio, err := engine.Render(context.Background(), "tes", TTSConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(audio, wav) {
+				t.Errorf("audio = %d bytes, want %d", len(audio), len(wav))
+			}
+		})
+	}
+}
+
+func TestHTTPEngineErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		status int
+		body   string
+		want   string
+	}{
+		{"server error", "wav", http.StatusInternalServerError, "voice not loaded\n", "engine error 500: voice not loaded"},
+		{"empty error body", "wav", http.StatusServiceUnavailable, "", "engine error 503: Service Unavailable"},
+		{"long error body", "wav", http.StatusBadRequest, strings.Repeat("x", 300), "engine error 400: " + strings.Repeat("x", 200)},
+		{"not a WAV", "wav", http.StatusOK, "<html>oops</html>", "invalid audio from engine"},
+		{"bad base64", "base64", http.StatusOK, "!!!", "invalid audio from engine"},
+		{"bad json", "json:audio", http.StatusOK, "{", "invalid audio from engine"},
+		{"missing field", "json:data.audio", http.StatusOK, `{"data":{}}`, `field "data.audio" not found`},
+		{"field not an object", "json:data.audio", http.StatusOK, `{"data":"x"}`, `field "data.audio" not found`},
+		{"base64 not a WAV", "base64", http.StatusOK, base64.StdEncoding.EncodeToString([]byte("hello")), "invalid audio from engine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newHTTPEngineServer(t, tt.status, []byte(tt.body))
+			engine, err := NewHTTPEngine(HTTPEngineConfig{Name: "engine", URL: server.URL, Format: tt.format})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = engine.Render(context.Background(), "tes", TTSConfig{})
+			if err == nil {
+				t.Fatal("Render succeeded, want an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPEngineUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	target := server.URL
+	server.Close()
+
+	engine, err := NewHTTPEngine(HTTPEngineConfig{Name: "engine", URL: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Render(context.Background(), "tes", TTSConfig{}); err == nil || !strings.HasPrefix(err.Error(), "engine: ") {
+		t.Errorf("error = %v, want a connection error prefixed with the engine name", err)
+	}
+}
+
+func TestNewHTTPEngineValidates(t *testing.T) {
+	tests := []struct {
+		name   string
+		config HTTPEngineConfig
+	}{
+		{"method", HTTPEngineConfig{URL: "http://localhost", Method: "PUT"}},
+		{"format", HTTPEngineConfig{URL: "http://localhost", Format: "mp3"}},
+		{"url", HTTPEngineConfig{URL: "http://local host:%zz"}},
+		{"params", HTTPEngineConfig{URL: "http://localhost", Params: "a=%zz"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHTTPEngine(tt.config); err == nil {
+				t.Errorf("NewHTTPEngine(%+v) succeeded, want an error", tt.config)
+			}
+		})
+	}
+
+	engine, err := NewHTTPEngine(HTTPEngineConfig{URL: "http://localhost", Languages: []string{" ID ", "en"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if engine.Name() != "http" {
+		t.Errorf("default name = %q, want http", engine.Name())
+	}
+	if got := engine.Capabilities().Languages; len(got) != 2 || got[0] != "id" || got[1] != "en" {
+		t.Errorf("languages = %v, want normalized [id en]", got)
+	}
+}
+
+func TestHTTPEngineVoices(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Voice
+	}{
+		{
+			"marytts lines",
+			"cmu-slt-hsmm en_US female hmm\n\nistc-lucia-hsmm it female hmm\n",
+			[]Voice{
+				{Name: "cmu-slt-hsmm", Language: "en-US", Engine: "marytts"},
+				{Name: "istc-lucia-hsmm", Language: "it", Engine: "marytts"},
+			},
+		},
+		{
+			"json array",
+			`["ana","budi"]`,
+			[]Voice{
+				{Name: "ana", Engine: "marytts"},
+				{Name: "budi", Engine: "marytts"},
+			},
+		},
+		{
+			"name only",
+			"solo\n",
+			[]Voice{{Name: "solo", Engine: "marytts"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, captured := newHTTPEngineServer(t, http.StatusOK, []byte(tt.body))
+			engine, err := NewHTTPEngine(HTTPEngineConfig{Name: "marytts", URL: server.URL, VoicesURL: server.URL + "/voices"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			voices, err := engine.Voices()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if captured.path != "/voices" {
+				t.Errorf("voices path = %q", captured.path)
+			}
+			if len(voices) != len(tt.want) {
+				t.Fatalf("voices = %+v, want %+v", voices, tt.want)
+			}
+			for i := range voices {
+				if voices[i].Name != tt.want[i].Name || voices[i].Language != tt.want[i].Language || voices[i].Engine != tt.want[i].Engine {
+					t.Errorf("voice %d = %+v, want %+v", i, voices[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPEngineVoicesErrors(t *testing.T) {
+	server, _ := newHTTPEngineServer(t, http.StatusNotFound, nil)
+	engine, err := NewHTTPEngine(HTTPEngineConfig{Name: "marytts", URL: server.URL, VoicesURL: server.URL + "/voices"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Voices(); err == nil || !strings.Contains(err.Error(), "marytts error 404") {
+		t.Errorf("error = %v, want a 404 error", err)
+	}
+
+	// Tanpa VoicesURL daftar kosong, bukan error
+	engine, err = NewHTTPEngine(HTTPEngineConfig{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if voices, err := engine.Voices(); err != nil || len(voices) != 0 {
+		t.Errorf("Voices() = %v, %v, want an empty list", voices, err)
+	}
+}
+
+func TestMaryTTSPresetSkipsIndonesian(t *testing.T) {
+	wav := testWAV(16000, 100)
+	var locales []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := r.URL.Query().Get("LOCALE")
+		locales = append(locales, locale)
+		if locale != "en_US" {
+			http.Error(w, "No voice for locale "+locale, http.StatusBadRequest)
+			return
+		}
+		w.Write(wav)
+	}))
+	defer server.Close()
+
+	config := MaryTTSPreset
+	config.URL = server.URL + "/process"
+	mary, err := NewHTTPEngine(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := NewEngineRegistry(mary, NewBuiltinEngine())
+
+	// Bahasa Indonesia langsung ke engine lain, tanpa memanggil MaryTTS
+	for i := 0; i < breakerThreshold; i++ {
+		audio, err := registry.Synthesize(context.Background(), "Selamat pagi.", TTSConfig{Language: "id-ID"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if audio.Engine != "builtin" {
+			t.Errorf("id-ID rendered by %s, want builtin", audio.Engine)
+		}
+	}
+	if len(locales) != 0 {
+		t.Errorf("MaryTTS called for %v, want no Indonesian requests", locales)
+	}
+
+	audio, err := registry.Synthesize(context.Background(), "Good morning.", TTSConfig{Language: "en-US"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audio.Engine != "marytts" {
+		t.Errorf("en-US rendered by %s, want marytts", audio.Engine)
+	}
+	for _, info := range registry.Info() {
+		if info.Name == "marytts" && info.Breaker != "closed" {
+			t.Errorf("marytts breaker = %s, want closed", info.Breaker)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PiperEngine engine neural piper yang berjalan lokal dengan model .onnx.
+//
+// Model dibaca dari modelDir: setiap <nama>.onnx beserta <nama>.onnx.json
+// (konfigurasi dari piper, berisi kode bahasa). Nama model dipakai sebagai
+// nama voice, mis. id_ID-news_tts-medium.
+type PiperEngine struct {
+	binary   string
+	modelDir string
+}
+
+// piperModel satu model yang terpasang
+type piperModel struct {
+	Name     string
+	Path     string
+	Language string // Mis. id-ID
+}
+
+// NewPiperEngine membuat engine piper dari binary dan direktori model
+func NewPiperEngine(binary, modelDir string) *PiperEngine {
+	return &PiperEngine{binary: binary, modelDir: modelDir}
+}
+
+func (e *PiperEngine) Name() string {
+	return "piper"
+}
+
+// Capabilities bahasa diambil dari model yang terpasang
+func (e *PiperEngine) Capabilities() EngineCapabilities {
+	languages := []string{}
+	seen := map[string]bool{}
+	for _, model := range e.models() {
+		base := strings.ToLower(strings.SplitN(model.Language, "-", 2)[0])
+		if base != "" && !seen[base] {
+			seen[base] = true
+			languages = append(languages, base)
+		}
+	}
+	return EngineCapabilities{
+		Languages: languages,
+		Formats:   []string{"wav"},
+		Marks:     true,
+	}
+}
+
+// Available jika binary piper ada dan minimal satu model terpasang
+func (e *PiperEngine) Available() bool {
+	if _, err := exec.LookPath(e.binary); err != nil {
+		return false
+	}
+	return len(e.models()) > 0
+}
+
+// Render menjalankan piper dengan teks dari stdin dan menulis WAV ke file
+func (e *PiperEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	model, err := e.selectModel(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// length_scale adalah kebalikan kecepatan: 2.0 = dua kali lebih lambat
+	lengthScale := 1.0 / config.Speed
+
+	audio, err := renderToFile(func(outFile string) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, e.binary,
+			"--model", model.Path,
+			"--output_file", outFile,
+			"--length_scale", fmt.Sprintf("%.2f", lengthScale),
+		)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// piper tidak punya pengaturan volume
+	if config.Volume < 1.0 {
+		return scaleWAV(audio, config.Volume)
+	}
+	return audio, nil
+}
+
+// Voices mengembalikan model yang terpasang
+func (e *PiperEngine) Voices() ([]Voice, error) {
+	voices := []Voice{}
+	for _, model := range e.models() {
+		voices = append(voices, Voice{Name: model.Name, Language: model.Language, Engine: e.Name()})
+	}
+	return voices, nil
+}
+
+// selectModel memilih model dari config.voice, atau model pertama untuk bahasanya
+func (e *PiperEngine) selectModel(config TTSConfig) (piperModel, error) {
+	models := e.models()
+	if config.Voice != "" {
+		for _, model := range models {
+			if model.Name == config.Voice {
+				return model, nil
+			}
+		}
+	}
+
+	base := strings.ToLower(strings.SplitN(config.Language, "-", 2)[0])
+	for _, model := range models {
+		if strings.HasPrefix(strings.ToLower(model.Language), base) {
+			return model, nil
+		}
+	}
+	return piperModel{}, fmt.Errorf("no piper model for language %s in %s", config.Language, e.modelDir)
+}
+
+// models membaca model .onnx di modelDir, diurutkan berdasarkan nama
+func (e *PiperEngine) models() []piperModel {
+	paths, _ := filepath.Glob(filepath.Join(e.modelDir, "*.onnx"))
+	sort.Strings(paths)
+
+	models := []piperModel{}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".onnx")
+		models = append(models, piperModel{
+			Name:     name,
+			Path:     path,
+			Language: piperModelLanguage(path, name),
+		})
+	}
+	return models
+}
+
+// piperModelLanguage membaca kode bahasa dari <model>.onnx.json, atau dari
+// awalan nama file (id_ID-...) jika konfigurasinya tidak ada
+func piperModelLanguage(path, name string) string {
+	code := strings.SplitN(name, "-", 2)[0]
+	if data, err := os.ReadFile(path + ".json"); err == nil {
+		var modelConfig struct {
+			Language struct {
+				Code string `json:"code"`
+			} `json:"language"`
+		}
+		if json.Unmarshal(data, &modelConfig) == nil && modelConfig.Language.Code != "" {
+			code = modelConfig.Language.Code
+		}
+	}
+	return strings.ReplaceAll(code, "_", "-")
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// SAPIEngine engine System.Speech (Windows) lewat PowerShell
+type SAPIEngine struct{}
+
+func NewSAPIEngine() *SAPIEngine {
+	return &SAPIEngine{}
+}
+
+func (e *SAPIEngine) Name() string {
+	return "sapi"
+}
+
+func (e *SAPIEngine) Capabilities() EngineCapabilities {
+	return EngineCapabilities{
+		Languages: []string{"id", "en"},
+		Formats:   []string{"wav"},
+		Marks:     true,
+	}
+}
+
+func (e *SAPIEngine) Available() bool {
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	_, err := exec.LookPath("powershell")
+	return err == nil
+}
+
+// SpeakCommand memutar teks langsung ke speaker
+func (e *SAPIEngine) SpeakCommand(ctx context.Context, text string, config TTSConfig) (*exec.Cmd, error) {
+	return exec.CommandContext(ctx, "powershell", "-Command", sapiScript(text, config, "")), nil
+}
+
+// Render menulis WAV ke file
+func (e *SAPIEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	return renderToFile(func(outFile string) *exec.Cmd {
+		return exec.CommandContext(ctx, "powershell", "-Command", sapiScript(text, config, outFile))
+	})
+}
+
+// Voices membaca voice yang terpasang beserta bahasanya
+func (e *SAPIEngine) Voices() ([]Voice, error) {
+	script := `
+    Add-Type -AssemblyName System.speech
+    $speak = New-Object System.Speech.Synthesis.SpeechSynthesizer
+    $speak.GetInstalledVoices() | ForEach-Object {
+        $_.VoiceInfo.Name + "|" + $_.VoiceInfo.Culture.Name
+    }
+    `
+	output, err := exec.Command("powershell", "-Command", script).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	voices := []Voice{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		name, language, _ := strings.Cut(strings.TrimSpace(line), "|")
+		if name == "" {
+			continue
+		}
+		voices = append(voices, Voice{Name: name, Language: language, Engine: e.Name()})
+	}
+	return voices, nil
+}
+
+// sapiScript membuat script PowerShell. Voice tertentu dan output file bersifat opsional.
+func sapiScript(text string, config TTSConfig, outFile string) string {
+	extra := ""
+	if config.Voice != "" {
+		extra += fmt.Sprintf("\n    try { $speak.SelectVoice(\"%s\") } catch { }", escapePowerShellString(config.Voice))
+	}
+	if outFile != "" {
+		extra += fmt.Sprintf("\n    $speak.SetOutputToWaveFile(\"%s\")", escapePowerShellString(outFile))
+	}
+
+	return fmt.Sprintf(`
+    Add-Type -AssemblyName System.speech
+    $speak = New-Object System.Speech.Synthesis.SpeechSynthesizer
+
+    # Set language
+    $culture = New-Object System.Globalization.CultureInfo("%s")
+    try {
+        $speak.SelectVoiceByHints([System.Speech.Synthesis.VoiceGender]::Female,
+                                   [System.Speech.Synthesis.VoiceAge]::Adult,
+                                   0, $culture)
+    } catch {
+        # Use default voice if specific language not found
+    }
+
+    # Set rate (-10 to 10)
+    $rate = %d
+    if ($rate -lt -10) { $rate = -10 }
+    if ($rate -gt 10) { $rate = 10 }
+    $speak.Rate = $rate
+
+    # Set volume (0-100)
+    $volume = %d
+    if ($volume -lt 0) { $volume = 0 }
+    if ($volume -gt 100) { $volume = 100 }
+    $speak.Volume = $volume
+    %s
+
+    # Speak
+    $speak.Speak("%s")
+    $speak.Dispose()
+    `,
+		escapePowerShellString(config.Language),
+		int((config.Speed-1)*10), // Convert 0.5-2.0 to -5 to 10
+		int(config.Volume*100),
+		extra,
+		escapePowerShellString(text))
+}
+
+// escapePowerShellString escape string untuk PowerShell
+func escapePowerShellString(s string) string {
+	// Escape quotes dan karakter khusus
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "`", "``")
+	s = strings.ReplaceAll(s, "$", "`$")
+	return s
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// SayEngine engine `say` bawaan macOS
+type SayEngine struct{}
+
+func NewSayEngine() *SayEngine {
+	return &SayEngine{}
+}
+
+func (e *SayEngine) Name() string {
+	return "say"
+}
+
+func (e *SayEngine) Capabilities() EngineCapabilities {
+	return EngineCapabilities{
+		Languages: []string{"id", "en"},
+		Formats:   []string{"wav"},
+		Marks:     true,
+	}
+}
+
+func (e *SayEngine) Available() bool {
+	if runtime.GOOS != "darwin" {
+		return false
+	}
+	_, err := exec.LookPath("say")
+	return err == nil
+}
+
+// SpeakCommand memutar teks langsung ke speaker
+func (e *SayEngine) SpeakCommand(ctx context.Context, text string, config TTSConfig) (*exec.Cmd, error) {
+	return exec.CommandContext(ctx, "say", sayArgs(text, config, "")...), nil
+}
+
+// Render menulis WAV (PCM 16-bit) ke file
+func (e *SayEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	return renderToFile(func(outFile string) *exec.Cmd {
+		return exec.CommandContext(ctx, "say", sayArgs(text, config, outFile)...)
+	})
+}
+
+// Voices membaca daftar voice dari `say -v ?`
+func (e *SayEngine) Voices() ([]Voice, error) {
+	output, err := exec.Command("say", "-v", "?").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	voices := []Voice{}
+	for _, line := range strings.Split(string(output), "\n") {
+		// Format: "<nama voice>  <locale>  # <contoh kalimat>"; nama bisa berisi spasi
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			continue
+		}
+		voices = append(voices, Voice{
+			Name:     strings.Join(parts[:len(parts)-1], " "),
+			Language: strings.ReplaceAll(parts[len(parts)-1], "_", "-"),
+			Engine:   e.Name(),
+		})
+	}
+	return voices, nil
+}
+
+// sayArgs menyusun argumen say. Jika outFile diisi, audio ditulis ke file.
+func sayArgs(text string, config TTSConfig, outFile string) []string {
+	// Pilih voice berdasarkan bahasa, kecuali voice diminta langsung
+	voice := "Damayanti" // Voice Indonesia
+	if strings.EqualFold(strings.SplitN(config.Language, "-", 2)[0], "en") {
+		voice = "Alex" // Voice English US
+	}
+	if config.Voice != "" {
+		voice = config.Voice
+	}
+	args := []string{"-v", voice}
+
+	// Speed rate (say command menggunakan rate dalam WPM)
+	// Default rate adalah 175 WPM, adjust berdasarkan config.Speed
+	rate := int(175 * config.Speed)
+	if rate < 50 {
+		rate = 50
+	}
+	if rate > 400 {
+		rate = 400
+	}
+	args = append(args, "-r", fmt.Sprintf("%d", rate))
+
+	// Volume lewat perintah [[volm]] di awal teks; say tidak punya flag
+	// volume (-a memilih perangkat audio). Volume 0 berarti bisu.
+	if config.Volume >= 0 && config.Volume != 1.0 {
+		text = fmt.Sprintf("[[volm %.2f]] %s", config.Volume, text)
+	}
+
+	// Render ke file (PCM 16-bit supaya bisa dibaca sebagai WAV)
+	if outFile != "" {
+		args = append(args, "-o", outFile, "--data-format=LEI16@22050")
+	}
+
+	return append(args, text)
+}
@@ -0,0 +1,465 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Engine dianggap rusak setelah gagal sebanyak ini berturut-turut
+	breakerThreshold = 3
+	// Lama engine yang rusak dilewati sebelum dicoba lagi
+	breakerCooldown = 30 * time.Second
+	// Daftar voice engine dipakai ulang selama ini untuk mencari pemilik config.voice
+	voiceListTTL = 5 * time.Minute
+)
+
+var (
+	// ErrUnknownEngine dikembalikan jika config.engine tidak dikenal atau tidak aktif
+	ErrUnknownEngine = errors.New("unknown TTS engine")
+	// ErrNoEngine dikembalikan jika tidak ada engine yang terpasang di host
+	ErrNoEngine = errors.New("no TTS engine available")
+)
+
+// EngineCapabilities kemampuan yang dideklarasikan sebuah engine
+type EngineCapabilities struct {
+	Languages []string `json:"languages"` // Kode bahasa dasar (id, en); kosong = semua
+	Formats   []string `json:"formats"`   // Format audio hasil Render
+	Marks     bool     `json:"marks"`     // Timing kata/kalimat bisa dihitung dari audionya
+}
+
+// Voice satu voice yang ditawarkan engine
+type Voice struct {
+	Name     string `json:"name"`
+	Language string `json:"language,omitempty"`
+	Engine   string `json:"engine"`
+}
+
+// Engine satu backend TTS yang bisa dipilih oleh EngineRegistry
+type Engine interface {
+	Name() string
+	Capabilities() EngineCapabilities
+	// Available mengecek apakah engine bisa dipakai di host ini
+	Available() bool
+	// Render merender teks (sudah dibersihkan dan dinormalisasi) menjadi WAV
+	Render(ctx context.Context, text string, config TTSConfig) ([]byte, error)
+	Voices() ([]Voice, error)
+}
+
+// SpeakerEngine engine yang juga bisa memutar suara langsung di speaker host
+type SpeakerEngine interface {
+	Engine
+	SpeakCommand(ctx context.Context, text string, config TTSConfig) (*exec.Cmd, error)
+}
+
+// EngineInfo keadaan satu engine untuk /api/voices
+type EngineInfo struct {
+	Name         string             `json:"name"`
+	Available    bool               `json:"available"`
+	Playback     bool               `json:"playback"`
+	Capabilities EngineCapabilities `json:"capabilities"`
+	Voices       []Voice            `json:"voices,omitempty"`
+	Breaker      string             `json:"breaker"` // closed, open atau half-open
+	Failures     int                `json:"failures,omitempty"`
+	LastError    string             `json:"last_error,omitempty"`
+}
+
+type engineState struct {
+	engine    Engine
+	failures  int // Gagal berturut-turut
+	openUntil time.Time
+	lastError string
+	voices    map[string]bool // Nama voice (huruf kecil), di-cache selama voiceListTTL
+	voicesAt  time.Time
+}
+
+// engineChain rantai fallback untuk satu request
+type engineChain struct {
+	engines []*engineState
+	// preferred engine yang dipakai jika tidak ada yang rusak, tanpa melihat circuit breaker
+	preferred *engineState
+	// voice engine pemilik config.voice; engine lain dijalankan dengan voice bawaannya
+	voice *engineState
+}
+
+// configFor mengembalikan config untuk engine di rantai: voice hanya
+// diteruskan ke engine pemiliknya
+func (c *engineChain) configFor(state *engineState, config TTSConfig) TTSConfig {
+	if state != c.voice {
+		config.Voice = ""
+	}
+	return config
+}
+
+// EngineRegistry menyimpan engine yang terdaftar dan urutan pemakaiannya.
+//
+// Setiap render mencoba engine satu per satu: engine yang diminta lewat
+// config.engine dulu, lalu engine yang mendukung bahasanya, lalu sisanya.
+// Engine yang gagal breakerThreshold kali berturut-turut dilewati selama
+// breakerCooldown (circuit breaker), kecuali tidak ada engine lain.
+type EngineRegistry struct {
+	mu      sync.Mutex
+	engines map[string]*engineState
+	order   []string
+}
+
+// NewEngineRegistry membuat registry; urutan default sama dengan urutan argumen
+func NewEngineRegistry(engines ...Engine) *EngineRegistry {
+	r := &EngineRegistry{engines: map[string]*engineState{}}
+	for _, engine := range engines {
+		r.engines[engine.Name()] = &engineState{engine: engine}
+		r.order = append(r.order, engine.Name())
+	}
+	return r
+}
+
+// SetOrder mengatur engine yang aktif beserta urutannya. Engine yang tidak
+// disebut tidak dipakai.
+func (r *EngineRegistry) SetOrder(names []string) error {
+	order := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := r.engines[name]; !ok {
+			return fmt.Errorf("%w %q", ErrUnknownEngine, name)
+		}
+		order = append(order, name)
+	}
+	if len(order) == 0 {
+		return ErrNoEngine
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.order = order
+	return nil
+}
+
+// Order mengembalikan nama engine yang aktif sesuai urutan
+func (r *EngineRegistry) Order() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.order...)
+}
+
+// Enabled mengecek apakah engine terdaftar dan aktif
+func (r *EngineRegistry) Enabled(name string) bool {
+	for _, enabled := range r.Order() {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Synthesize merender teks dengan engine pertama di rantai fallback yang berhasil.
+// Jika ctx dibatalkan (mis. client putus), render dihentikan tanpa mencoba
+// engine berikutnya.
+func (r *EngineRegistry) Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error) {
+	if err := validateSynthesisText(text); err != nil {
+		return nil, err
+	}
+	config = applyConfigDefaults(config)
+
+	chain, err := r.chain(config, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, state := range chain.engines {
+		engine := state.engine
+		engineConfig := chain.configFor(state, config)
+		audio, err := synthesizeChunks(ctx, text, engineConfig, func(index int, chunk string) ([]byte, error) {
+			return engine.Render(ctx, chunk, engineConfig)
+		})
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			r.recordFailure(state, err)
+			lastErr = fmt.Errorf("%s: %v", engine.Name(), err)
+			continue
+		}
+		r.recordSuccess(state)
+		audio.Engine = engine.Name()
+		// Audio dari engine cadangan tidak boleh di-cache seolah hasil engine utama
+		audio.Fallback = state != chain.preferred
+		return audio, nil
+	}
+	return nil, lastErr
+}
+
+// startSpeech menjalankan command speaker dari engine pertama yang berhasil start.
+// prepare dipanggil sebelum Start (mis. untuk process group).
+func (r *EngineRegistry) startSpeech(ctx context.Context, text string, config TTSConfig, prepare func(*exec.Cmd)) (*exec.Cmd, error) {
+	chain, err := r.chain(config, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, state := range chain.engines {
+		engine := state.engine.(SpeakerEngine)
+		cmd, err := engine.SpeakCommand(ctx, text, chain.configFor(state, config))
+		if err == nil {
+			prepare(cmd)
+			err = cmd.Start()
+		}
+		if err != nil {
+			r.recordFailure(state, err)
+			lastErr = fmt.Errorf("%s: %v", engine.Name(), err)
+			continue
+		}
+		r.recordSuccess(state)
+		return cmd, nil
+	}
+	return nil, lastErr
+}
+
+// Voices menggabungkan voice dari semua engine aktif yang terpasang
+func (r *EngineRegistry) Voices() []Voice {
+	voices := []Voice{}
+	for _, name := range r.Order() {
+		engine := r.engines[name].engine
+		if !engine.Available() {
+			continue
+		}
+		list, err := engine.Voices()
+		if err != nil {
+			log.Printf("Engine %s: failed to list voices: %v", name, err)
+			continue
+		}
+		voices = append(voices, list...)
+	}
+	return voices
+}
+
+// Info melaporkan kemampuan, voice dan status circuit breaker setiap engine aktif
+func (r *EngineRegistry) Info() []EngineInfo {
+	infos := []EngineInfo{}
+	for _, name := range r.Order() {
+		state := r.engines[name]
+		_, playback := state.engine.(SpeakerEngine)
+		info := EngineInfo{
+			Name:         name,
+			Available:    state.engine.Available(),
+			Playback:     playback,
+			Capabilities: state.engine.Capabilities(),
+		}
+		if info.Available {
+			if voices, err := state.engine.Voices(); err == nil {
+				info.Voices = voices
+			}
+		}
+
+		r.mu.Lock()
+		info.Breaker = breakerState(state, time.Now())
+		info.Failures = state.failures
+		info.LastError = state.lastError
+		r.mu.Unlock()
+
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// chain menyusun rantai fallback untuk config. Jika speaker diisi, hanya
+// engine yang bisa memutar di host yang diikutkan.
+func (r *EngineRegistry) chain(config TTSConfig, speaker bool) (*engineChain, error) {
+	if config.Engine != "" && !r.Enabled(config.Engine) {
+		return nil, fmt.Errorf("%w %q", ErrUnknownEngine, config.Engine)
+	}
+	order := r.Order()
+
+	type candidate struct {
+		state *engineState
+		rank  int
+		base  int // rank tanpa circuit breaker
+	}
+	candidates := []candidate{}
+	now := time.Now()
+
+	r.mu.Lock()
+	for _, name := range order {
+		state := r.engines[name]
+		if speaker {
+			if _, ok := state.engine.(SpeakerEngine); !ok {
+				continue
+			}
+		}
+
+		// Urutan: engine yang diminta (atau pemilik voice), engine yang
+		// mendukung bahasanya, engine lain, lalu engine yang circuit
+		// breaker-nya terbuka
+		rank := 2
+		switch {
+		case name == config.Engine:
+			rank = 0
+		case supportsLanguage(state.engine.Capabilities(), config.Language):
+			rank = 1
+		}
+		base := rank
+		if breakerState(state, now) == "open" {
+			rank += 3
+		}
+		candidates = append(candidates, candidate{state: state, rank: rank, base: base})
+	}
+	r.mu.Unlock()
+
+	// Available bisa memanggil LookPath, jadi dicek di luar lock
+	available := candidates[:0]
+	for _, c := range candidates {
+		if c.state.engine.Available() {
+			available = append(available, c)
+		}
+	}
+	candidates = available
+	if len(candidates) == 0 {
+		return nil, ErrNoEngine
+	}
+	chain := &engineChain{}
+
+	// Voice milik engine lain tidak dikirim ke engine yang diminta; tanpa
+	// engine yang diminta, pemilik voice didahulukan
+	if config.Voice != "" {
+		for _, c := range candidates {
+			if c.state.engine.Name() == config.Engine && r.hasVoice(c.state, config.Voice) {
+				chain.voice = c.state
+			}
+		}
+		for i, c := range candidates {
+			if chain.voice != nil {
+				break
+			}
+			if r.hasVoice(c.state, config.Voice) {
+				chain.voice = c.state
+				if config.Engine == "" {
+					candidates[i].rank -= candidates[i].base
+					candidates[i].base = 0
+				}
+			}
+		}
+	}
+
+	// Engine utama dipilih dari urutan registry sebelum breaker diperhitungkan
+	preferredRank := 0
+	for _, c := range candidates {
+		if chain.preferred == nil || c.base < preferredRank {
+			chain.preferred, preferredRank = c.state, c.base
+		}
+	}
+	// Voice yang tidak dikenal engine mana pun tetap dikirim ke engine utama
+	if config.Voice != "" && chain.voice == nil {
+		chain.voice = chain.preferred
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].rank < candidates[j].rank
+	})
+	for _, c := range candidates {
+		chain.engines = append(chain.engines, c.state)
+	}
+	return chain, nil
+}
+
+// hasVoice mengecek apakah engine menawarkan voice. Daftar voice di-cache
+// supaya render tidak menjalankan `--voices` atau request HTTP setiap kali.
+func (r *EngineRegistry) hasVoice(state *engineState, voice string) bool {
+	r.mu.Lock()
+	voices := state.voices
+	fresh := time.Since(state.voicesAt) < voiceListTTL
+	r.mu.Unlock()
+
+	if !fresh {
+		voices = map[string]bool{}
+		list, err := state.engine.Voices()
+		if err != nil {
+			log.Printf("Engine %s: failed to list voices: %v", state.engine.Name(), err)
+		}
+		for _, v := range list {
+			voices[strings.ToLower(v.Name)] = true
+		}
+
+		r.mu.Lock()
+		state.voices = voices
+		state.voicesAt = time.Now()
+		r.mu.Unlock()
+	}
+	return voices[strings.ToLower(voice)]
+}
+
+func (r *EngineRegistry) recordFailure(state *engineState, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state.failures++
+	state.lastError = err.Error()
+	if state.failures >= breakerThreshold {
+		if state.failures == breakerThreshold {
+			log.Printf("Engine %s: %d failures in a row, skipping it for %v", state.engine.Name(), state.failures, breakerCooldown)
+		}
+		state.openUntil = time.Now().Add(breakerCooldown)
+	}
+}
+
+func (r *EngineRegistry) recordSuccess(state *engineState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state.failures = 0
+	state.lastError = ""
+	state.openUntil = time.Time{}
+}
+
+// breakerState status circuit breaker; setelah cooldown engine boleh dicoba lagi (half-open)
+func breakerState(state *engineState, now time.Time) string {
+	switch {
+	case state.failures < breakerThreshold:
+		return "closed"
+	case now.Before(state.openUntil):
+		return "open"
+	}
+	return "half-open"
+}
+
+// supportsLanguage mencocokkan bahasa dasar (id-ID -> id) dengan kemampuan engine
+func supportsLanguage(caps EngineCapabilities, language string) bool {
+	if len(caps.Languages) == 0 {
+		return true
+	}
+	base := strings.ToLower(strings.SplitN(language, "-", 2)[0])
+	for _, supported := range caps.Languages {
+		if supported == base {
+			return true
+		}
+	}
+	return false
+}
+
+// renderToFile menjalankan command yang menulis WAV ke file sementara lalu membaca hasilnya
+func renderToFile(build func(outFile string) *exec.Cmd) ([]byte, error) {
+	file, err := os.CreateTemp("", "lansia-tts-*.wav")
+	if err != nil {
+		return nil, err
+	}
+	outFile := file.Name()
+	file.Close()
+	defer os.Remove(outFile)
+
+	cmd := build(outFile)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("render failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return os.ReadFile(outFile)
+}
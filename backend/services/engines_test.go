@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// blockingEngine engine palsu yang merender sampai ctx dibatalkan
+type blockingEngine struct {
+	started chan struct{}
+}
+
+func (e *blockingEngine) Name() string { return "blocking" }
+
+func (e *blockingEngine) Capabilities() EngineCapabilities {
+	return EngineCapabilities{Languages: []string{"id"}, Formats: []string{"wav"}}
+}
+
+func (e *blockingEngine) Available() bool { return true }
+
+func (e *blockingEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	close(e.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (e *blockingEngine) Voices() ([]Voice, error) { return nil, nil }
+
+func TestEngineRegistryStopsWhenCancelled(t *testing.T) {
+	blocking := &blockingEngine{started: make(chan struct{})}
+	flaky := &flakyEngine{}
+	registry := NewEngineRegistry(blocking, flaky)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := registry.Synthesize(ctx, "Halo semua.", TTSConfig{Language: "id-ID"})
+		done <- err
+	}()
+	<-blocking.started
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Synthesize error = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Synthesize did not stop after cancel")
+	}
+	if flaky.renders != 0 {
+		t.Errorf("fallback engine rendered %d times after cancel", flaky.renders)
+	}
+	for _, info := range registry.Info() {
+		if info.Failures != 0 {
+			t.Errorf("%s failures = %d, cancel should not count", info.Name, info.Failures)
+		}
+	}
+}
+
+// voiceEngine engine palsu dengan daftar voice yang mencatat voice tiap render
+type voiceEngine struct {
+	name   string
+	voices []string
+	fail   bool
+	got    []string
+}
+
+func (e *voiceEngine) Name() string { return e.name }
+
+func (e *voiceEngine) Capabilities() EngineCapabilities {
+	return EngineCapabilities{Formats: []string{"wav"}}
+}
+
+func (e *voiceEngine) Available() bool { return true }
+
+func (e *voiceEngine) Render(ctx context.Context, text string, config TTSConfig) ([]byte, error) {
+	e.got = append(e.got, config.Voice)
+	if e.fail {
+		return nil, errors.New("model rusak")
+	}
+	return testWAV(16000, 160), nil
+}
+
+func (e *voiceEngine) Voices() ([]Voice, error) {
+	voices := []Voice{}
+	for _, name := range e.voices {
+		voices = append(voices, Voice{Name: name, Engine: e.name})
+	}
+	return voices, nil
+}
+
+func TestEngineRegistryVoiceOnlyForOwner(t *testing.T) {
+	tests := []struct {
+		name       string
+		voice      string
+		engine     string
+		piperFails bool
+		wantEngine string
+		wantPiper  []string
+		wantEspeak []string
+	}{
+		// Pemilik voice gagal, engine cadangan memakai voice bawaannya
+		{"owner fails", "id_ID-news_tts-medium", "", true, "espeak", []string{"id_ID-news_tts-medium"}, []string{""}},
+		// Pemilik voice didahulukan walau ada di belakang urutan
+		{"owner first", "id", "", false, "espeak", nil, []string{"id"}},
+		// Engine yang diminta tidak menerima voice milik engine lain
+		{"requested engine", "id", "piper", true, "espeak", []string{""}, []string{"id"}},
+		// Voice tidak dikenal hanya dikirim ke engine utama
+		{"unknown voice", "asing", "", true, "espeak", []string{"asing"}, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			piper := &voiceEngine{name: "piper", voices: []string{"id_ID-news_tts-medium"}, fail: tt.piperFails}
+			espeak := &voiceEngine{name: "espeak", voices: []string{"id", "en"}}
+			registry := NewEngineRegistry(piper, espeak)
+
+			audio, err := registry.Synthesize(context.Background(), "Halo.", TTSConfig{Language: "id-ID", Voice: tt.voice, Engine: tt.engine})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if audio.Engine != tt.wantEngine {
+				t.Errorf("engine = %s, want %s", audio.Engine, tt.wantEngine)
+			}
+			if !slices.Equal(piper.got, tt.wantPiper) || !slices.Equal(espeak.got, tt.wantEspeak) {
+				t.Errorf("voices piper %q espeak %q, want %q and %q", piper.got, espeak.got, tt.wantPiper, tt.wantEspeak)
+			}
+		})
+	}
+}
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	formantSampleRate = 16000
+	formantFrameMs    = 5.0
+	formantBaseF0     = 120.0 // Nada dasar (Hz)
+)
+
+// phonemeClass jenis bunyi, menentukan cara fonem dibangkitkan
+type phonemeClass int
+
+const (
+	classVowel phonemeClass = iota
+	classNasal
+	classLiquid
+	classGlide
+	classStop
+	classFricative
+	classAffricate
+	classAspirate
+	classGlottal
+)
+
+// phonemeSpec parameter target satu fonem untuk synthesizer formant
+type phonemeSpec struct {
+	Class      phonemeClass
+	F1, F2, F3 float64 // Formant (Hz); untuk konsonan: locus transisi
+	Voiced     bool
+	DurationMs float64
+	Amp        float64 // Amplitudo suara (voicing)
+	Noise      float64 // Amplitudo desis (frikasi)
+	NoiseFreq  float64 // Pusat desis (Hz)
+	NoiseBW    float64
+}
+
+// phonemeSpecs nilai formant kira-kira untuk penutur dewasa
+var phonemeSpecs = map[rune]phonemeSpec{
+	'a': {Class: classVowel, F1: 730, F2: 1250, F3: 2500, Voiced: true, DurationMs: 95, Amp: 1.0},
+	'i': {Class: classVowel, F1: 280, F2: 2250, F3: 2950, Voiced: true, DurationMs: 90, Amp: 0.85},
+	'u': {Class: classVowel, F1: 310, F2: 870, F3: 2250, Voiced: true, DurationMs: 90, Amp: 0.85},
+	'e': {Class: classVowel, F1: 460, F2: 1950, F3: 2600, Voiced: true, DurationMs: 95, Amp: 0.95},
+	'E': {Class: classVowel, F1: 500, F2: 1450, F3: 2450, Voiced: true, DurationMs: 70, Amp: 0.85},
+	'o': {Class: classVowel, F1: 480, F2: 900, F3: 2450, Voiced: true, DurationMs: 95, Amp: 0.95},
+
+	'm': {Class: classNasal, F1: 260, F2: 1000, F3: 2300, Voiced: true, DurationMs: 65, Amp: 0.45},
+	'n': {Class: classNasal, F1: 260, F2: 1550, F3: 2600, Voiced: true, DurationMs: 60, Amp: 0.45},
+	'N': {Class: classNasal, F1: 260, F2: 2000, F3: 2600, Voiced: true, DurationMs: 65, Amp: 0.45},
+	'J': {Class: classNasal, F1: 260, F2: 2150, F3: 2900, Voiced: true, DurationMs: 65, Amp: 0.45},
+
+	'l': {Class: classLiquid, F1: 360, F2: 1300, F3: 2700, Voiced: true, DurationMs: 55, Amp: 0.6},
+	'r': {Class: classLiquid, F1: 420, F2: 1300, F3: 1700, Voiced: true, DurationMs: 55, Amp: 0.55},
+	'w': {Class: classGlide, F1: 300, F2: 700, F3: 2200, Voiced: true, DurationMs: 45, Amp: 0.6},
+	'y': {Class: classGlide, F1: 280, F2: 2200, F3: 3000, Voiced: true, DurationMs: 45, Amp: 0.6},
+
+	'p': {Class: classStop, F1: 250, F2: 900, F3: 2200, DurationMs: 70, Noise: 0.25, NoiseFreq: 900, NoiseBW: 1200},
+	'b': {Class: classStop, F1: 250, F2: 900, F3: 2200, Voiced: true, DurationMs: 60, Noise: 0.2, NoiseFreq: 900, NoiseBW: 1200},
+	't': {Class: classStop, F1: 250, F2: 1750, F3: 2700, DurationMs: 70, Noise: 0.35, NoiseFreq: 4000, NoiseBW: 2000},
+	'd': {Class: classStop, F1: 250, F2: 1750, F3: 2700, Voiced: true, DurationMs: 60, Noise: 0.3, NoiseFreq: 3800, NoiseBW: 2000},
+	'k': {Class: classStop, F1: 250, F2: 2100, F3: 2500, DurationMs: 75, Noise: 0.35, NoiseFreq: 2000, NoiseBW: 1000},
+	'g': {Class: classStop, F1: 250, F2: 2100, F3: 2500, Voiced: true, DurationMs: 65, Noise: 0.3, NoiseFreq: 2000, NoiseBW: 1000},
+
+	'c': {Class: classAffricate, F1: 250, F2: 2200, F3: 2900, DurationMs: 100, Noise: 0.25, NoiseFreq: 3000, NoiseBW: 1500},
+	'j': {Class: classAffricate, F1: 250, F2: 2200, F3: 2900, Voiced: true, DurationMs: 90, Noise: 0.18, NoiseFreq: 3000, NoiseBW: 1500},
+
+	's': {Class: classFricative, F1: 250, F2: 1700, F3: 2700, DurationMs: 95, Noise: 0.18, NoiseFreq: 5500, NoiseBW: 2000},
+	'z': {Class: classFricative, F1: 250, F2: 1700, F3: 2700, Voiced: true, DurationMs: 80, Amp: 0.2, Noise: 0.12, NoiseFreq: 5500, NoiseBW: 2000},
+	'S': {Class: classFricative, F1: 250, F2: 2100, F3: 2800, DurationMs: 100, Noise: 0.22, NoiseFreq: 3000, NoiseBW: 1500},
+	'f': {Class: classFricative, F1: 250, F2: 1000, F3: 2300, DurationMs: 85, Noise: 0.1, NoiseFreq: 6000, NoiseBW: 4000},
+	'x': {Class: classFricative, F1: 250, F2: 1600, F3: 2500, DurationMs: 85, Noise: 0.35, NoiseFreq: 1600, NoiseBW: 800},
+
+	'h': {Class: classAspirate, DurationMs: 55, Noise: 0.13},
+	'?': {Class: classGlottal, DurationMs: 45},
+}
+
+// formantFrame parameter synthesizer untuk satu frame 5 ms
+type formantFrame struct {
+	F0         float64
+	F1, F2, F3 float64
+	Voice      float64 // Amplitudo sumber suara (glottal)
+	Aspiration float64 // Desis yang lewat formant (h, hembusan setelah letupan)
+	Frication  float64 // Desis yang lewat resonator NoiseFreq
+	NoiseFreq  float64
+	NoiseBW    float64
+}
+
+// resonator filter IIR dua kutub (resonator digital Klatt)
+type resonator struct {
+	a, b, c float64
+	y1, y2  float64
+}
+
+func (r *resonator) set(freq, bandwidth float64) {
+	t := 1.0 / formantSampleRate
+	r.c = -math.Exp(-2 * math.Pi * bandwidth * t)
+	r.b = 2 * math.Exp(-math.Pi*bandwidth*t) * math.Cos(2*math.Pi*freq*t)
+	r.a = 1 - r.b - r.c
+}
+
+func (r *resonator) process(x float64) float64 {
+	y := r.a*x + r.b*r.y1 + r.c*r.y2
+	r.y2, r.y1 = r.y1, y
+	return y
+}
+
+// synthesizeFormant merender teks Indonesia menjadi WAV mono 16-bit
+func synthesizeFormant(text string, speed, volume float64) []byte {
+	if speed <= 0 {
+		speed = 1.0
+	}
+	frames := planFrames(phonemizeIndonesian(text), speed)
+	pcm := normalizePCM(renderFrames(frames), volume)
+
+	format := wavFormat{
+		AudioFormat:   1,
+		Channels:      1,
+		SampleRate:    formantSampleRate,
+		ByteRate:      formantSampleRate * 2,
+		BlockAlign:    2,
+		BitsPerSample: 16,
+	}
+	return encodeWAV(format, pcm)
+}
+
+// planFrames mengubah deret kata dan jeda menjadi frame parameter, dengan
+// transisi formant antar fonem dan intonasi yang turun sepanjang frasa
+func planFrames(tokens []phraseToken, speed float64) []formantFrame {
+	// Frasa dipisah oleh jeda; intonasi dihitung per frasa
+	type plannedPhoneme struct {
+		spec     phonemeSpec
+		duration float64
+		stressed bool
+	}
+	frames := []formantFrame{}
+	silence := func(ms float64) {
+		for i := 0; i < int(ms/formantFrameMs); i++ {
+			frames = append(frames, formantFrame{F0: formantBaseF0, F1: 500, F2: 1500, F3: 2500, NoiseFreq: 1000, NoiseBW: 1000})
+		}
+	}
+
+	// Jeda kecil di awal supaya tidak ada klik
+	silence(30)
+
+	phrase := []plannedPhoneme{}
+	flush := func(question bool) {
+		if len(phrase) == 0 {
+			return
+		}
+		total := 0.0
+		for _, p := range phrase {
+			total += p.duration
+		}
+
+		elapsed := 0.0
+		prev := formantFrame{F1: 500, F2: 1500, F3: 2500}
+		for i, p := range phrase {
+			// Formant konsonan berikutnya/sebelumnya dipakai sebagai locus transisi
+			next := p.spec
+			for j := i + 1; j < len(phrase); j++ {
+				if phrase[j].spec.Class == classVowel {
+					next = phrase[j].spec
+					break
+				}
+			}
+
+			count := int(p.duration / formantFrameMs)
+			if count < 1 {
+				count = 1
+			}
+			for f := 0; f < count; f++ {
+				position := float64(f) / float64(count)
+				progress := (elapsed + position*p.duration) / total
+
+				// Deklinasi nada 1.1 -> 0.85, naik di akhir kalimat tanya
+				pitch := 1.1 - 0.25*progress
+				if question && progress > 0.75 {
+					pitch += (progress - 0.75) * 1.6
+				}
+				if p.stressed {
+					pitch *= 1.08
+				}
+
+				frame := phonemeFrame(p.spec, next, position)
+				frame.F0 = formantBaseF0 * pitch
+
+				// Formant bergeser halus dari fonem sebelumnya selama 35% awal
+				if p.spec.Class != classStop && p.spec.Class != classGlottal {
+					blend := math.Min(1, position/0.35)
+					frame.F1 = prev.F1 + (frame.F1-prev.F1)*blend
+					frame.F2 = prev.F2 + (frame.F2-prev.F2)*blend
+					frame.F3 = prev.F3 + (frame.F3-prev.F3)*blend
+				}
+				frames = append(frames, frame)
+			}
+			elapsed += p.duration
+			prev = formantFrame{F1: p.spec.F1, F2: p.spec.F2, F3: p.spec.F3}
+			if p.spec.Class == classAspirate || p.spec.Class == classGlottal {
+				prev = formantFrame{F1: next.F1, F2: next.F2, F3: next.F3}
+			}
+		}
+		phrase = phrase[:0]
+	}
+
+	for i, token := range tokens {
+		if token.PauseMs > 0 {
+			flush(token.Question)
+			silence(token.PauseMs / speed)
+			continue
+		}
+		for j, symbol := range token.Phonemes {
+			spec, ok := phonemeSpecs[symbol]
+			if !ok {
+				continue
+			}
+			duration := spec.DurationMs
+			stressed := j == token.Stress
+			if stressed {
+				duration *= 1.3
+			}
+			// Suku kata terakhir sebelum jeda diperpanjang
+			if spec.Class == classVowel && j >= len(token.Phonemes)-2 && (i+1 == len(tokens) || tokens[i+1].PauseMs > 0) {
+				duration *= 1.4
+			}
+			phrase = append(phrase, plannedPhoneme{spec: spec, duration: duration / speed, stressed: stressed})
+		}
+	}
+	flush(false)
+	silence(60)
+	return frames
+}
+
+// phonemeFrame parameter satu frame di posisi (0-1) dalam fonem. next adalah
+// vokal berikutnya, untuk mewarnai letupan dan hembusan.
+func phonemeFrame(spec, next phonemeSpec, position float64) formantFrame {
+	frame := formantFrame{
+		F1: spec.F1, F2: spec.F2, F3: spec.F3,
+		NoiseFreq: spec.NoiseFreq, NoiseBW: spec.NoiseBW,
+	}
+	if frame.NoiseFreq == 0 {
+		frame.NoiseFreq, frame.NoiseBW = 1000, 1000
+	}
+
+	// Amplitudo naik/turun di tepi fonem supaya tidak ada klik
+	edge := math.Min(1, math.Min(position, 1-position)/0.15)
+
+	switch spec.Class {
+	case classVowel, classNasal, classGlide:
+		frame.Voice = spec.Amp * math.Min(1, 0.6+edge)
+	case classLiquid:
+		frame.Voice = spec.Amp * math.Min(1, 0.6+edge)
+		if spec.F3 < 2000 {
+			// r getar: amplitudo dimodulasi dua kali ketukan
+			frame.Voice *= 0.55 + 0.45*math.Abs(math.Cos(position*2*math.Pi))
+		}
+	case classStop:
+		// Penutupan (hening atau voice bar), lalu letupan singkat di 20% akhir
+		if spec.Voiced {
+			frame.Voice = 0.12
+			frame.F1 = 180
+		}
+		if position > 0.8 {
+			frame.Frication = spec.Noise
+			frame.Aspiration = spec.Noise * 0.4
+			frame.F1, frame.F2, frame.F3 = next.F1, next.F2, next.F3
+		}
+	case classAffricate:
+		if spec.Voiced {
+			frame.Voice = 0.15
+		}
+		if position > 0.45 {
+			frame.Frication = spec.Noise * edge
+		}
+	case classFricative:
+		frame.Frication = spec.Noise * edge
+		if spec.Voiced {
+			frame.Voice = spec.Amp * edge
+		}
+	case classAspirate:
+		frame.Aspiration = spec.Noise * edge
+		frame.F1, frame.F2, frame.F3 = next.F1, next.F2, next.F3
+	case classGlottal:
+		// Hening
+	}
+	return frame
+}
+
+// renderFrames membangkitkan sampel dari frame: sumber glottal dan desis
+// lewat tiga resonator bertingkat, ditambah desis frikasi paralel
+func renderFrames(frames []formantFrame) []float64 {
+	samplesPerFrame := int(formantSampleRate * formantFrameMs / 1000)
+	samples := make([]float64, 0, len(frames)*samplesPerFrame)
+
+	noise := rand.New(rand.NewSource(1))
+	var r1, r2, r3, rf resonator
+	phase := 0.0
+	prevPulse := 0.0
+	prev := formantFrame{}
+	if len(frames) > 0 {
+		prev = frames[0]
+	}
+
+	for _, frame := range frames {
+		r1.set(frame.F1, 90)
+		r2.set(frame.F2, 110)
+		r3.set(frame.F3, 170)
+		rf.set(frame.NoiseFreq, frame.NoiseBW)
+
+		for n := 0; n < samplesPerFrame; n++ {
+			// Amplitudo diinterpolasi per sampel dari frame sebelumnya
+			t := float64(n) / float64(samplesPerFrame)
+			voice := prev.Voice + (frame.Voice-prev.Voice)*t
+			aspiration := prev.Aspiration + (frame.Aspiration-prev.Aspiration)*t
+			frication := prev.Frication + (frame.Frication-prev.Frication)*t
+			f0 := prev.F0 + (frame.F0-prev.F0)*t
+
+			// Pulsa glottal Rosenberg, diturunkan (radiasi bibir)
+			phase += f0 / formantSampleRate
+			if phase >= 1 {
+				phase -= 1
+			}
+			pulse := 0.0
+			switch {
+			case phase < 0.4:
+				pulse = 0.5 * (1 - math.Cos(math.Pi*phase/0.4))
+			case phase < 0.56:
+				pulse = math.Cos(math.Pi * (phase - 0.4) / 0.32)
+			}
+			source := (pulse - prevPulse) * 8 * voice
+			prevPulse = pulse
+
+			white := noise.Float64()*2 - 1
+			cascade := r3.process(r2.process(r1.process(source + white*aspiration)))
+			samples = append(samples, cascade+rf.process(white)*frication)
+		}
+		prev = frame
+	}
+	return samples
+}
+
+// normalizePCM menyamakan puncak ke 0.9 * volume lalu mengubahnya ke PCM 16-bit
+func normalizePCM(samples []float64, volume float64) []byte {
+	peak := 0.0
+	for _, s := range samples {
+		peak = math.Max(peak, math.Abs(s))
+	}
+	gain := 0.0
+	if peak > 0 {
+		gain = 0.9 * volume / peak
+	}
+
+	pcm := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		value := int16(math.Max(-32767, math.Min(32767, s*gain*32767)))
+		pcm[2*i] = byte(uint16(value))
+		pcm[2*i+1] = byte(uint16(value) >> 8)
+	}
+	return pcm
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// phraseToken satu kata (deret fonem) atau jeda dalam frasa. Satu huruf per
+// fonem supaya mudah dibaca: N = ng, J = ny, S = sy, x = kh, E = e pepet,
+// c = c (tʃ), j = j (dʒ), ? = hamzah (k di akhir kata).
+type phraseToken struct {
+	Phonemes []rune
+	Stress   int     // Indeks fonem vokal yang diberi tekanan, -1 jika tidak ada
+	PauseMs  float64 // Lebih dari 0 untuk jeda
+	Question bool    // Jeda ini mengakhiri kalimat tanya
+}
+
+// digitWords ejaan angka untuk teks yang belum dinormalisasi
+var digitWords = []string{"nol", "satu", "dua", "tiga", "empat", "lima", "enam", "tujuh", "delapan", "sembilan"}
+
+// phonemizeIndonesian mengubah teks menjadi deret kata dan jeda dengan aturan
+// ejaan bahasa Indonesia, yang hampir selalu dibaca sesuai tulisannya
+func phonemizeIndonesian(text string) []phraseToken {
+	tokens := []phraseToken{}
+	word := []rune{}
+
+	flush := func() {
+		if len(word) > 0 {
+			phonemes := wordPhonemes(string(word))
+			if len(phonemes) > 0 {
+				tokens = append(tokens, phraseToken{Phonemes: phonemes, Stress: stressIndex(phonemes)})
+			}
+			word = word[:0]
+		}
+	}
+	pause := func(ms float64, question bool) {
+		flush()
+		// Jeda berurutan digabung, yang terpanjang dipakai
+		if n := len(tokens); n > 0 && tokens[n-1].PauseMs > 0 {
+			if ms > tokens[n-1].PauseMs {
+				tokens[n-1].PauseMs = ms
+			}
+			tokens[n-1].Question = tokens[n-1].Question || question
+			return
+		}
+		tokens = append(tokens, phraseToken{PauseMs: ms, Stress: -1, Question: question})
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r):
+			word = append(word, r)
+		case unicode.IsDigit(r):
+			flush()
+			if r >= '0' && r <= '9' {
+				word = append(word, []rune(digitWords[r-'0'])...)
+				flush()
+			}
+		case r == '.' || r == '!' || r == ';':
+			pause(350, false)
+		case r == '?':
+			pause(350, true)
+		case r == ',' || r == ':' || r == '(' || r == ')':
+			pause(200, false)
+		case r == '-' || r == '\'':
+			// Kata ulang dan singkatan dibaca menyambung
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+// wordPhonemes menerapkan aturan grafem ke fonem untuk satu kata
+func wordPhonemes(word string) []rune {
+	letters := []rune(word)
+	phonemes := []rune{}
+
+	for i := 0; i < len(letters); i++ {
+		r := letters[i]
+		next := rune(0)
+		if i+1 < len(letters) {
+			next = letters[i+1]
+		}
+
+		// Digraf (termasuk ejaan lama tj, dj, oe)
+		switch {
+		case r == 'n' && next == 'g':
+			phonemes = append(phonemes, 'N')
+			i++
+			continue
+		case r == 'n' && next == 'y':
+			phonemes = append(phonemes, 'J')
+			i++
+			continue
+		case r == 's' && next == 'y':
+			phonemes = append(phonemes, 'S')
+			i++
+			continue
+		case r == 'k' && next == 'h':
+			phonemes = append(phonemes, 'x')
+			i++
+			continue
+		case r == 't' && next == 'j':
+			phonemes = append(phonemes, 'c')
+			i++
+			continue
+		case r == 'd' && next == 'j':
+			phonemes = append(phonemes, 'j')
+			i++
+			continue
+		case r == 'o' && next == 'e':
+			phonemes = append(phonemes, 'u')
+			i++
+			continue
+		}
+
+		switch r {
+		case 'a', 'i', 'u', 'o':
+			phonemes = append(phonemes, r)
+		case 'é', 'è', 'ê':
+			phonemes = append(phonemes, 'e')
+		case 'e':
+			phonemes = append(phonemes, 'E')
+		case 'q':
+			phonemes = append(phonemes, 'k')
+		case 'v':
+			phonemes = append(phonemes, 'f')
+		case 'x':
+			phonemes = append(phonemes, 'k', 's')
+		case 'k':
+			// k di akhir kata dibaca sebagai hamzah (tidak -> tida?)
+			if i == len(letters)-1 && i > 0 && isVowelPhoneme(phonemes[len(phonemes)-1]) {
+				phonemes = append(phonemes, '?')
+			} else {
+				phonemes = append(phonemes, 'k')
+			}
+		case 'b', 'c', 'd', 'f', 'g', 'h', 'j', 'l', 'm', 'n', 'p', 'r', 's', 't', 'w', 'y', 'z':
+			phonemes = append(phonemes, r)
+		default:
+			// Huruf beraksen lain dibaca seperti huruf dasarnya
+			if base := foldLetter(r); base != r && base != 0 {
+				phonemes = append(phonemes, wordPhonemes(string(base))...)
+			}
+		}
+	}
+
+	// e di suku kata terakhir yang terbuka umumnya é (sate, tempe)
+	if n := len(phonemes); n > 1 && phonemes[n-1] == 'E' {
+		phonemes[n-1] = 'e'
+	}
+	return phonemes
+}
+
+// stressIndex tekanan jatuh di vokal kedua dari belakang, kecuali vokal itu
+// e pepet; kata bersuku satu ditekan di vokalnya
+func stressIndex(phonemes []rune) int {
+	vowels := []int{}
+	for i, p := range phonemes {
+		if isVowelPhoneme(p) {
+			vowels = append(vowels, i)
+		}
+	}
+	switch len(vowels) {
+	case 0:
+		return -1
+	case 1:
+		return vowels[0]
+	}
+	penultimate := vowels[len(vowels)-2]
+	if phonemes[penultimate] == 'E' {
+		return vowels[len(vowels)-1]
+	}
+	return penultimate
+}
+
+func isVowelPhoneme(p rune) bool {
+	return strings.ContainsRune("aiueoE", p)
+}
+
+// foldLetter menghapus aksen umum (à -> a, ü -> u)
+func foldLetter(r rune) rune {
+	switch r {
+	case 'à', 'á', 'â', 'ä', 'å':
+		return 'a'
+	case 'ì', 'í', 'î', 'ï':
+		return 'i'
+	case 'ò', 'ó', 'ô', 'ö':
+		return 'o'
+	case 'ù', 'ú', 'û', 'ü':
+		return 'u'
+	case 'ë':
+		return 'e'
+	case 'ç':
+		return 'c'
+	case 'ñ':
+		return 'n'
+	}
+	return 0
+}
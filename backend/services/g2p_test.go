@@ -0,0 +1,76 @@
+package services
+
+import "testing"
+
+func TestWordPhonemes(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"bangun", "baNun"},
+		{"mengganggu", "mENgaNgu"},
+		{"ngeri", "NEri"},
+		{"nyanyi", "JaJi"},
+		{"khusus", "xusus"},
+		{"akhir", "axir"},
+		{"syukur", "Sukur"},
+		{"tidak", "tida?"},
+		{"kuda", "kuda"},
+		{"enam", "Enam"},
+		{"sate", "sate"},
+		{"tjinta", "cinta"},
+		{"djakarta", "jakarta"},
+		{"soekarno", "sukarno"},
+		{"taxi", "taksi"},
+		{"vitamin", "fitamin"},
+		{"qari", "kari"},
+		{"café", "cafe"},
+	}
+	for _, tt := range tests {
+		if got := string(wordPhonemes(tt.word)); got != tt.want {
+			t.Errorf("wordPhonemes(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestStressIndex(t *testing.T) {
+	tests := []struct {
+		phonemes string
+		want     int
+	}{
+		{"baNun", 1}, // Vokal kedua dari belakang
+		{"Enam", 2},  // e pepet tidak ditekan
+		{"ya", 1},    // Satu suku kata
+		{"xr", -1},   // Tanpa vokal
+	}
+	for _, tt := range tests {
+		if got := stressIndex([]rune(tt.phonemes)); got != tt.want {
+			t.Errorf("stressIndex(%q) = %d, want %d", tt.phonemes, got, tt.want)
+		}
+	}
+}
+
+func TestPhonemizeIndonesian(t *testing.T) {
+	tokens := phonemizeIndonesian("3 anak, mau?")
+	want := []struct {
+		phonemes string
+		pause    bool
+		question bool
+	}{
+		{"tiga", false, false},
+		{"ana?", false, false},
+		{"", true, false},
+		{"mau", false, false},
+		{"", true, true},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens, want %d: %+v", len(tokens), len(want), tokens)
+	}
+	for i, w := range want {
+		token := tokens[i]
+		if string(token.Phonemes) != w.phonemes || (token.PauseMs > 0) != w.pause || token.Question != w.question {
+			t.Errorf("token %d = %q (pause %v, question %v), want %q (pause %v, question %v)",
+				i, string(token.Phonemes), token.PauseMs, token.Question, w.phonemes, w.pause, w.question)
+		}
+	}
+}
@@ -0,0 +1,480 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// JobState status job sintesis
+type JobState string
+
+const (
+	JobPending   JobState = "pending"
+	JobRunning   JobState = "running"
+	JobCompleted JobState = "completed"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+// MaxJobTextRunes batas panjang teks untuk satu job (satu bab/artikel panjang)
+const MaxJobTextRunes = 200000
+
+var (
+	// ErrJobNotFound dikembalikan jika ID job tidak dikenal
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotReady dikembalikan jika audio job belum selesai dirender
+	ErrJobNotReady = errors.New("job audio is not ready")
+	// ErrNoCaptions dikembalikan untuk job lama yang dirender tanpa timing
+	ErrNoCaptions = errors.New("captions are not available for this job")
+)
+
+// Job sintesis teks panjang yang berjalan di background
+type Job struct {
+	ID              string     `json:"id"`
+	State           JobState   `json:"state"`
+	Text            string     `json:"text"`
+	Config          TTSConfig  `json:"config"`
+	Chunks          []string   `json:"chunks"`
+	ChunksDone      int        `json:"chunks_done"`
+	Progress        float64    `json:"progress"` // 0.0 - 1.0
+	AudioDurationMs float64    `json:"audio_duration_ms,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+// Finished mengecek apakah job sudah berhenti (berhasil, gagal, atau dibatalkan)
+func (j *Job) Finished() bool {
+	return j.State == JobCompleted || j.State == JobFailed || j.State == JobCancelled
+}
+
+// JobManager menjalankan dan menyimpan job sintesis di disk.
+//
+// Setiap job disimpan di <dir>/<id>/: job.json untuk metadata, chunk-NNNN.wav
+// dan chunk-NNNN.json (timing) untuk potongan yang sudah selesai, serta audio.wav
+// dan captions.json untuk hasil akhir. Job yang belum selesai saat backend mati
+// akan dilanjutkan dari potongan terakhir.
+type JobManager struct {
+	dir     string
+	service TTSService
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	cancels map[string]context.CancelFunc
+	slots   chan struct{} // Membatasi job yang dirender bersamaan
+}
+
+// NewJobManager memuat job yang tersimpan di dir dan melanjutkan yang belum selesai
+func NewJobManager(dir string, service TTSService, workers int) (*JobManager, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	m := &JobManager{
+		dir:     dir,
+		service: service,
+		jobs:    map[string]*Job{},
+		cancels: map[string]context.CancelFunc{},
+		slots:   make(chan struct{}, workers),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		job, err := m.load(entry.Name())
+		if err != nil {
+			log.Printf("Jobs: skipping %s: %v", entry.Name(), err)
+			continue
+		}
+		m.jobs[job.ID] = job
+
+		if !job.Finished() {
+			log.Printf("Jobs: resuming %s at chunk %d/%d", job.ID, job.ChunksDone, len(job.Chunks))
+			job.State = JobPending
+			m.startLocked(job)
+		}
+	}
+
+	return m, nil
+}
+
+// Create membuat job baru dan langsung menjadwalkannya
+func (m *JobManager) Create(text string, config TTSConfig) (*Job, error) {
+	if utf8.RuneCountInString(text) > MaxJobTextRunes {
+		return nil, fmt.Errorf("text too long (max %d characters)", MaxJobTextRunes)
+	}
+
+	chunks := chunkText(text, defaultChunkRunes)
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:        newID(),
+		State:     JobPending,
+		Text:      text,
+		Config:    applyConfigDefaults(config),
+		Chunks:    chunks,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.jobDir(job.ID), 0o755); err != nil {
+		return nil, err
+	}
+	if err := m.saveLocked(job); err != nil {
+		return nil, err
+	}
+	m.jobs[job.ID] = job
+	m.startLocked(job)
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// Get mengembalikan salinan job
+func (m *JobManager) Get(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// List mengembalikan semua job, yang terbaru di depan
+func (m *JobManager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// Cancel menghentikan job yang belum selesai. Potongan yang sedang
+// dirender dibiarkan selesai, lalu job berhenti.
+func (m *JobManager) Cancel(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if !job.Finished() {
+		if cancel := m.cancels[id]; cancel != nil {
+			cancel()
+		}
+		m.finishLocked(job, JobCancelled, "")
+	}
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// Delete membatalkan job (jika perlu) lalu menghapus file-nya
+func (m *JobManager) Delete(id string) error {
+	if _, err := m.Cancel(id); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.jobs, id)
+	return os.RemoveAll(m.jobDir(id))
+}
+
+// AudioPath mengembalikan lokasi file audio dari job yang sudah selesai
+func (m *JobManager) AudioPath(id string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return "", ErrJobNotFound
+	}
+	if job.State != JobCompleted {
+		return "", ErrJobNotReady
+	}
+	return filepath.Join(m.jobDir(id), "audio.wav"), nil
+}
+
+// startLocked menjalankan job di goroutine tersendiri
+func (m *JobManager) startLocked(job *Job) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancels[job.ID] = cancel
+	go m.run(ctx, job.ID)
+}
+
+func (m *JobManager) run(ctx context.Context, id string) {
+	// Tunggu slot worker kosong
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-ctx.Done():
+		return
+	}
+
+	var count int
+	for {
+		m.mu.Lock()
+		job := m.jobs[id]
+		if job == nil || job.Finished() || ctx.Err() != nil {
+			m.mu.Unlock()
+			return
+		}
+		count = len(job.Chunks)
+		if job.ChunksDone >= count {
+			m.mu.Unlock()
+			break
+		}
+		index := job.ChunksDone
+		chunk := job.Chunks[index]
+		config := job.Config
+		if job.State != JobRunning {
+			job.State = JobRunning
+			job.UpdatedAt = time.Now()
+			m.saveLocked(job)
+		}
+		m.mu.Unlock()
+
+		audio, err := m.renderChunk(ctx, chunk, config)
+		if err == nil {
+			err = m.saveChunk(id, index, audio)
+		}
+
+		m.mu.Lock()
+		if job.Finished() {
+			m.mu.Unlock()
+			return
+		}
+		if err != nil {
+			m.finishLocked(job, JobFailed, fmt.Sprintf("chunk %d: %v", index+1, err))
+			m.mu.Unlock()
+			return
+		}
+		job.ChunksDone = index + 1
+		job.Progress = float64(job.ChunksDone) / float64(len(job.Chunks))
+		job.AudioDurationMs += audio.AudioDuration
+		job.UpdatedAt = time.Now()
+		m.saveLocked(job)
+		m.mu.Unlock()
+	}
+
+	// Semua potongan selesai, gabungkan menjadi satu file
+	err := m.assemble(id, count)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := m.jobs[id]
+	if job == nil || job.Finished() {
+		return
+	}
+	if err != nil {
+		m.finishLocked(job, JobFailed, err.Error())
+		return
+	}
+	m.finishLocked(job, JobCompleted, "")
+}
+
+func (m *JobManager) renderChunk(ctx context.Context, chunk string, config TTSConfig) (*AudioResult, error) {
+	// Teks asli yang dikirim supaya teks di caption sama dengan sumbernya,
+	// service sendiri yang membersihkan teks sebelum ke engine
+	if _, err := ValidateText(chunk); err != nil {
+		return nil, err
+	}
+	return m.service.Synthesize(ctx, chunk, config)
+}
+
+// saveChunk menyimpan audio dan timing satu potongan
+func (m *JobManager) saveChunk(id string, index int, audio *AudioResult) error {
+	marks, err := json.Marshal(audio.Marks)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(m.chunkMarksPath(id, index), marks); err != nil {
+		return err
+	}
+	return writeFileAtomic(m.chunkPath(id, index), audio.Data)
+}
+
+// Captions mengembalikan cue caption per kalimat dari job yang sudah selesai
+func (m *JobManager) Captions(id string) ([]Mark, error) {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		return nil, ErrJobNotFound
+	}
+	if job.State != JobCompleted {
+		m.mu.Unlock()
+		return nil, ErrJobNotReady
+	}
+	m.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(m.jobDir(id), "captions.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCaptions
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cues []Mark
+	if err := json.Unmarshal(data, &cues); err != nil {
+		return nil, err
+	}
+	return cues, nil
+}
+
+// assemble menggabungkan chunk-NNNN.wav menjadi audio.wav, timing tiap potongan
+// menjadi captions.json, lalu menghapus potongannya
+func (m *JobManager) assemble(id string, count int) error {
+	parts := make([][]byte, 0, count)
+	marks := []Mark{}
+	offsetMs := 0.0
+	for i := 0; i < count; i++ {
+		data, err := os.ReadFile(m.chunkPath(id, i))
+		if err != nil {
+			return err
+		}
+		parts = append(parts, data)
+
+		// Potongan yang dirender sebelum ada timing tidak punya file marks
+		if chunkMarks, err := m.loadChunkMarks(id, i); err == nil {
+			for _, mark := range chunkMarks {
+				mark.TimeMs += offsetMs
+				marks = append(marks, mark)
+			}
+		}
+		offsetMs += wavDurationMs(data)
+	}
+
+	audio, err := concatWAV(parts)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(m.jobDir(id), "audio.wav"), audio); err != nil {
+		return err
+	}
+
+	cues, err := json.Marshal(BuildCues(marks))
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(m.jobDir(id), "captions.json"), cues); err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		os.Remove(m.chunkPath(id, i))
+		os.Remove(m.chunkMarksPath(id, i))
+	}
+	return nil
+}
+
+func (m *JobManager) loadChunkMarks(id string, index int) ([]Mark, error) {
+	data, err := os.ReadFile(m.chunkMarksPath(id, index))
+	if err != nil {
+		return nil, err
+	}
+	var marks []Mark
+	err = json.Unmarshal(data, &marks)
+	return marks, err
+}
+
+func (m *JobManager) finishLocked(job *Job, state JobState, message string) {
+	now := time.Now()
+	job.State = state
+	job.Error = message
+	job.UpdatedAt = now
+	job.CompletedAt = &now
+	if state == JobCompleted {
+		job.Progress = 1
+	}
+	delete(m.cancels, job.ID)
+	m.saveLocked(job)
+}
+
+func (m *JobManager) saveLocked(job *Job) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = writeFileAtomic(filepath.Join(m.jobDir(job.ID), "job.json"), data)
+	if err != nil {
+		log.Printf("Jobs: failed to save %s: %v", job.ID, err)
+	}
+	return err
+}
+
+func (m *JobManager) load(id string) (*Job, error) {
+	data, err := os.ReadFile(filepath.Join(m.jobDir(id), "job.json"))
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	if job.ID != id {
+		return nil, fmt.Errorf("job id mismatch")
+	}
+	return &job, nil
+}
+
+func (m *JobManager) jobDir(id string) string {
+	return filepath.Join(m.dir, id)
+}
+
+func (m *JobManager) chunkPath(id string, index int) string {
+	return filepath.Join(m.jobDir(id), fmt.Sprintf("chunk-%04d.wav", index))
+}
+
+func (m *JobManager) chunkMarksPath(id string, index int) string {
+	return filepath.Join(m.jobDir(id), fmt.Sprintf("chunk-%04d.json", index))
+}
+
+// writeFileAtomic menulis ke file sementara lalu rename, supaya file
+// tidak setengah jadi jika backend mati di tengah penulisan
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
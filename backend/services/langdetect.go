@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Deteksi bahasa offline dengan model trigram huruf (naive Bayes) yang
+// dilatih dari contoh teks setiap bahasa. Dipakai jika config.language
+// kosong atau "auto": bahasa ditebak untuk seluruh teks dan per kalimat,
+// lalu setiap bagian dibaca dengan voice bahasanya.
+
+// LanguageAuto nilai config.language untuk deteksi bahasa otomatis
+const LanguageAuto = "auto"
+
+const (
+	// Kalimat dengan huruf sebanyak ini atau kurang terlalu pendek untuk
+	// ditebak sendiri ("OK.", "Download"), dan ikut bahasa kalimat sebelumnya
+	minDetectLetters = 12
+	// Kalimat baru berganti bahasa hanya jika tebakannya cukup yakin
+	minDetectConfidence = 0.95
+	// Jumlah n-gram yang dibayangkan ada, untuk smoothing trigram yang tidak
+	// pernah muncul di contoh
+	detectVocabulary = 20000
+)
+
+// LanguageSegment bagian teks dengan bahasa hasil deteksi
+type LanguageSegment struct {
+	Segment
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+}
+
+// languageProfile model trigram satu bahasa
+type languageProfile struct {
+	language string // Kode bahasa yang dipakai engine, mis. id-ID
+	logProbs map[string]float64
+	unseen   float64 // Log-probabilitas trigram yang tidak ada di contoh
+}
+
+// newLanguageProfile melatih model dari contoh teks
+func newLanguageProfile(language, sample string) languageProfile {
+	counts := map[string]int{}
+	total := 0
+	for _, trigram := range trigrams(sample) {
+		counts[trigram]++
+		total++
+	}
+
+	denominator := math.Log(float64(total + detectVocabulary))
+	profile := languageProfile{
+		language: language,
+		logProbs: make(map[string]float64, len(counts)),
+		unseen:   -denominator,
+	}
+	for trigram, count := range counts {
+		profile.logProbs[trigram] = math.Log(float64(count+1)) - denominator
+	}
+	return profile
+}
+
+// languageProfiles bahasa yang bisa dideteksi; yang pertama juga menjadi
+// bahasa default jika teks tidak bisa ditebak
+var languageProfiles = []languageProfile{
+	newLanguageProfile("id-ID", indonesianSample),
+	newLanguageProfile("en-US", englishSample),
+}
+
+// trigrams memecah teks menjadi trigram huruf kecil per kata, dengan spasi
+// sebagai penanda awal dan akhir kata (" ya", "ya ")
+func trigrams(text string) []string {
+	result := []string{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			result = append(result, string(runes[i:i+3]))
+		}
+	}
+	return result
+}
+
+// DetectLanguage menebak bahasa teks. Confidence 0 berarti teks tidak punya
+// huruf sama sekali dan bahasa default yang dikembalikan.
+func DetectLanguage(text string) (string, float64) {
+	grams := trigrams(text)
+	if len(grams) == 0 {
+		return languageProfiles[0].language, 0
+	}
+
+	scores := make([]float64, len(languageProfiles))
+	best := 0
+	for i, profile := range languageProfiles {
+		for _, trigram := range grams {
+			if logProb, ok := profile.logProbs[trigram]; ok {
+				scores[i] += logProb
+			} else {
+				scores[i] += profile.unseen
+			}
+		}
+		if scores[i] > scores[best] {
+			best = i
+		}
+	}
+
+	// Probabilitas posterior (softmax dari skor log) bahasa terbaik
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - scores[best])
+	}
+	return languageProfiles[best].language, 1 / sum
+}
+
+// DetectSegments memecah teks per kalimat, menebak bahasa setiap kalimat,
+// lalu menggabungkan kalimat berurutan yang bahasanya sama. Kalimat pendek
+// atau yang tebakannya ragu ikut bahasa kalimat sebelumnya (atau bahasa
+// seluruh teks untuk kalimat pertama).
+func DetectSegments(text string) []LanguageSegment {
+	sentences := SplitSentences(text)
+	if len(sentences) == 0 {
+		return nil
+	}
+	current, _ := DetectLanguage(text)
+
+	runes := []rune(text)
+	segments := []LanguageSegment{}
+	for _, sentence := range sentences {
+		if language, confidence := DetectLanguage(sentence.Text); confidence >= minDetectConfidence && countLetters(sentence.Text) > minDetectLetters {
+			current = language
+		}
+		if last := len(segments) - 1; last >= 0 && segments[last].Language == current {
+			segments[last].End = sentence.End
+			segments[last].Text = string(runes[segments[last].Start:sentence.End])
+			continue
+		}
+		segments = append(segments, LanguageSegment{Segment: sentence, Language: current})
+	}
+
+	for i := range segments {
+		_, segments[i].Confidence = DetectLanguage(segments[i].Text)
+	}
+	return segments
+}
+
+// segmentLanguage bahasa bagian yang memuat offset rune pos, atau fallback
+func segmentLanguage(segments []LanguageSegment, pos int, fallback string) string {
+	for _, segment := range segments {
+		if pos >= segment.Start && pos < segment.End {
+			return segment.Language
+		}
+	}
+	return fallback
+}
+
+func countLetters(text string) int {
+	count := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			count++
+		}
+	}
+	return count
+}
+
+// LanguageTTSService membungkus TTSService sehingga config.language "auto"
+// (atau kosong) diganti bahasa hasil deteksi. Teks campuran dirender per
+// bagian dengan voice bahasanya masing-masing lalu digabung berurutan.
+type LanguageTTSService struct {
+	TTSService
+}
+
+func NewLanguageTTSService(service TTSService) *LanguageTTSService {
+	return &LanguageTTSService{TTSService: service}
+}
+
+// Speak memutar teks dengan bahasa dominan; SystemTTSService berganti voice
+// per kalimat sesuai bagian hasil deteksi
+func (s *LanguageTTSService) Speak(text string, config TTSConfig) (*TTSResponse, error) {
+	if !autoLanguage(config) {
+		return s.TTSService.Speak(text, config)
+	}
+	config.Language, _ = DetectLanguage(text)
+	if segments := DetectSegments(text); len(segments) > 1 {
+		config.languages = segments
+	}
+	return s.TTSService.Speak(text, config)
+}
+
+// Synthesize merender teks; teks campuran dirender per bagian bahasa (cache
+// dan leksikon berlaku per bagian) lalu WAV dan mark-nya digabung
+func (s *LanguageTTSService) Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error) {
+	if !autoLanguage(config) {
+		return s.TTSService.Synthesize(ctx, text, config)
+	}
+	language, _ := DetectLanguage(text)
+	segments := DetectSegments(text)
+	if len(segments) <= 1 {
+		config.Language = language
+		audio, err := s.TTSService.Synthesize(ctx, text, config)
+		if err != nil {
+			return nil, err
+		}
+		audio.Language = language
+		return audio, nil
+	}
+
+	startTime := time.Now()
+	result := &AudioResult{Language: language, Languages: segments, Cached: true}
+	parts := [][]byte{}
+	offsetMs := 0.0
+	for _, segment := range segments {
+		segmentConfig := config
+		segmentConfig.Language = segment.Language
+		audio, err := s.TTSService.Synthesize(ctx, segment.Text, segmentConfig)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, audio.Data)
+
+		// Mark relatif terhadap bagian, geser ke teks dan audio penuh
+		for _, mark := range audio.Marks {
+			mark.Start += segment.Start
+			mark.End += segment.Start
+			mark.TimeMs += offsetMs
+			result.Marks = append(result.Marks, mark)
+		}
+		offsetMs += wavDurationMs(audio.Data)
+
+		result.ContentType = audio.ContentType
+		result.Cached = result.Cached && audio.Cached
+		result.Fallback = result.Fallback || audio.Fallback
+		if !strings.Contains(","+result.Engine+",", ","+audio.Engine+",") {
+			result.Engine = strings.TrimPrefix(result.Engine+","+audio.Engine, ",")
+		}
+	}
+
+	data, err := concatMixedWAV(parts)
+	if err != nil {
+		return nil, err
+	}
+	result.Data = data
+	result.AudioDuration = wavDurationMs(data)
+	result.Duration = time.Since(startTime).Seconds() * 1000
+	return result, nil
+}
+
+// Unwrap mengembalikan service yang dibungkus
+func (s *LanguageTTSService) Unwrap() TTSService {
+	return s.TTSService
+}
+
+func autoLanguage(config TTSConfig) bool {
+	return config.Language == "" || strings.EqualFold(config.Language, LanguageAuto)
+}
@@ -0,0 +1,45 @@
+package services
+
+// englishSample contoh teks bahasa Inggris untuk melatih deteksi bahasa:
+// berita, kesehatan, perbankan, belanja daring dan teks antarmuka
+const englishSample = `
+The city council announced that the vaccination schedule for older residents will begin on Monday at
+the nearest health center. Residents are asked to bring their identity card and arrive at the time they
+were given, so that the waiting lines stay short. Health workers will check blood pressure and general
+condition before the shot is given. Anyone who is feeling unwell should wait and register again next week.
+
+Today the weather is expected to be sunny with some clouds in the morning, followed by light to moderate
+rain across most of the region in the afternoon and evening. People are advised to drive carefully and
+always carry an umbrella or a raincoat. The weather service also warned about strong winds along the coast.
+
+To stay healthy, drink enough water, eat vegetables and fruit every day, and walk for at least thirty
+minutes. Getting enough sleep and not smoking help keep your heart strong. Remember to take your medicine
+as your doctor recommends and have your blood sugar checked regularly.
+
+Dear customer, we will never ask for your password, card number or secret code by phone, text message or
+email. If you receive a request like that, please contact our customer service right away. Your transaction
+has been processed successfully and your balance will be updated within a few minutes. Keep this payment
+receipt as a valid proof of purchase.
+
+Thank you for shopping with us. Your order is being packed and will be shipped by the courier soon. You
+can track the delivery from the my orders page. Items that are damaged or not as described can be returned
+within seven days after delivery. Free shipping is available on orders above a certain amount.
+
+Hi Mom, don't forget that the grandchildren are coming over this afternoon. Dad already bought the cake and
+the fruit they like. If anything comes up, just give us a call. Stay healthy and keep smiling. Good morning,
+how are you? I'm doing well, thank you. See you tomorrow after the service at church.
+
+Please sign in with your account or create a new one first. Open the settings menu to change the text size
+and the speech rate. Press the continue button to read the next article. This page contains important
+information that should be read carefully before you fill in the registration form. Click here to download
+the app, learn more, subscribe to our newsletter, or share this story with your friends.
+
+The president opened a new bridge that connects two districts in the province. The bridge is expected to
+shorten travel times for residents and strengthen the economy of the surrounding villages. Officials,
+community leaders and students attended the opening ceremony, which was lively even though it rained for
+a while.
+
+Prices of rice, cooking oil and eggs at the traditional markets started to fall toward the end of the month.
+Traders hope the supply from farmers will remain steady so that prices do not rise again. Shoppers said they
+were happy because they could save on their household spending.
+`
@@ -0,0 +1,45 @@
+package services
+
+// indonesianSample contoh teks bahasa Indonesia untuk melatih deteksi bahasa:
+// berita, kesehatan, perbankan, belanja daring dan pesan keluarga
+const indonesianSample = `
+Pemerintah daerah mengumumkan bahwa jadwal vaksinasi untuk warga lanjut usia akan dimulai pada hari Senin
+di puskesmas terdekat. Warga diminta membawa kartu tanda penduduk dan kartu keluarga, serta datang sesuai
+jam yang sudah ditentukan supaya tidak terjadi antrean panjang. Petugas kesehatan akan memeriksa tekanan
+darah dan kondisi tubuh sebelum penyuntikan dilakukan. Bagi yang sedang sakit, sebaiknya menunda dan
+mendaftar ulang minggu berikutnya.
+
+Cuaca hari ini diperkirakan cerah berawan pada pagi hari, kemudian hujan ringan hingga sedang di sebagian
+besar wilayah pada sore dan malam hari. Masyarakat diimbau untuk berhati-hati saat berkendara dan selalu
+membawa payung atau jas hujan. Badan meteorologi juga mengingatkan kemungkinan angin kencang di daerah pesisir.
+
+Untuk menjaga kesehatan, minumlah air putih yang cukup, makan sayur dan buah setiap hari, serta berjalan
+kaki paling sedikit tiga puluh menit. Tidur yang cukup dan tidak merokok membantu jantung tetap sehat.
+Jangan lupa minum obat sesuai anjuran dokter dan periksakan gula darah secara rutin.
+
+Nasabah yang terhormat, kami tidak pernah meminta kata sandi, nomor kartu atau kode rahasia melalui telepon,
+pesan singkat maupun surel. Jika Anda menerima permintaan seperti itu, segera hubungi layanan pelanggan
+kami. Transaksi Anda telah berhasil diproses dan saldo akan diperbarui dalam beberapa menit. Simpan bukti
+pembayaran ini sebagai tanda terima yang sah.
+
+Terima kasih telah berbelanja di toko kami. Pesanan Anda sedang dikemas dan akan segera dikirim oleh kurir.
+Anda dapat melacak pengiriman melalui halaman pesanan saya. Barang yang rusak atau tidak sesuai dapat
+dikembalikan dalam waktu tujuh hari setelah diterima. Gratis ongkos kirim untuk pembelian minimal tertentu.
+
+Ibu, jangan lupa nanti sore cucu-cucu mau datang ke rumah. Bapak sudah membeli kue dan buah kesukaan
+mereka. Kalau ada apa-apa, telepon saja ya. Semoga sehat selalu dan tetap semangat. Selamat pagi, apa
+kabar? Saya baik-baik saja, terima kasih. Sampai jumpa besok di masjid setelah salat Jumat.
+
+Silakan masuk dengan akun Anda atau daftar terlebih dahulu. Pilih menu pengaturan untuk mengubah ukuran
+huruf dan kecepatan suara. Tekan tombol lanjutkan untuk membaca artikel berikutnya. Halaman ini berisi
+informasi penting yang perlu dibaca dengan teliti sebelum mengisi formulir pendaftaran.
+
+Presiden meresmikan jembatan baru yang menghubungkan dua kabupaten di provinsi tersebut. Pembangunan
+jembatan ini diharapkan dapat mempercepat perjalanan warga dan meningkatkan perekonomian desa di
+sekitarnya. Sejumlah pejabat, tokoh masyarakat dan pelajar ikut hadir dalam acara peresmian yang
+berlangsung meriah, meskipun sempat diguyur hujan.
+
+Harga beras, minyak goreng dan telur di pasar tradisional mulai turun menjelang akhir bulan. Para pedagang
+berharap pasokan dari petani tetap lancar sehingga harga tidak kembali naik. Pembeli mengaku senang karena
+bisa menghemat pengeluaran rumah tangga.
+`
@@ -0,0 +1,564 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// LexiconMatch cara entri leksikon mencocokkan teks
+type LexiconMatch string
+
+const (
+	LexiconWord  LexiconMatch = "word"  // Kata atau frasa utuh
+	LexiconRegex LexiconMatch = "regex" // Regex (sintaks Go); bacaan boleh memakai $1, ${nama}
+)
+
+const (
+	// MaxLexiconEntries batas jumlah entri leksikon
+	MaxLexiconEntries = 5000
+	maxLexiconPattern = 200
+	maxLexiconReplace = 500
+)
+
+var (
+	// ErrLexiconNotFound dikembalikan jika ID entri tidak dikenal
+	ErrLexiconNotFound = errors.New("lexicon entry not found")
+	// ErrInvalidLexicon dikembalikan untuk entri atau file impor yang tidak valid
+	ErrInvalidLexicon = errors.New("invalid lexicon entry")
+)
+
+// lexiconCSVHeader kolom file CSV impor/ekspor
+var lexiconCSVHeader = []string{"pattern", "replacement", "match", "ignore_case", "language", "site"}
+
+// LexiconEntry satu koreksi pengucapan, mis. "Cianjur" dibaca "ci anjur"
+type LexiconEntry struct {
+	ID          string       `json:"id"`
+	Pattern     string       `json:"pattern"`     // Kata/frasa, atau regex jika match = regex
+	Replacement string       `json:"replacement"` // Teks yang diucapkan engine
+	Match       LexiconMatch `json:"match"`
+	IgnoreCase  bool         `json:"ignore_case"`
+	Language    string       `json:"language,omitempty"` // Kode bahasa dasar (id, en); kosong = semua
+	Site        string       `json:"site,omitempty"`     // Hostname, termasuk subdomain; kosong = semua situs
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Applies mengecek apakah entri berlaku untuk bahasa dan situs request
+func (e LexiconEntry) Applies(language, site string) bool {
+	if e.Language != "" && e.Language != lexiconLanguage(language) {
+		return false
+	}
+	if e.Site == "" {
+		return true
+	}
+	site = lexiconSite(site)
+	return site == e.Site || strings.HasSuffix(site, "."+e.Site)
+}
+
+// lexiconRules entri leksikon yang berlaku untuk satu request, sudah menjadi
+// aturan normalisasi. key ikut menjadi bagian key cache audio.
+type lexiconRules struct {
+	rules []normalizeRule
+	key   string
+}
+
+// Lexicon leksikon pengucapan yang bisa diubah user, disimpan sebagai satu
+// file JSON. Entri dipasang sebagai aturan normalisasi paling depan, jadi
+// menang atas aturan bawaan (angka, singkatan) di posisi yang sama.
+type Lexicon struct {
+	path string
+
+	mu       sync.RWMutex
+	entries  []LexiconEntry // Urut sesuai waktu dibuat
+	compiled map[string]*regexp.Regexp
+}
+
+// NewLexicon membuka leksikon di path; file dibuat saat entri pertama disimpan
+func NewLexicon(path string) (*Lexicon, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	l := &Lexicon{path: path, compiled: map[string]*regexp.Regexp{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []LexiconEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	for _, entry := range entries {
+		re, err := compileLexiconEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%s: entry %s: %v", path, entry.ID, err)
+		}
+		l.entries = append(l.entries, entry)
+		l.compiled[entry.ID] = re
+	}
+	return l, nil
+}
+
+// List mengembalikan semua entri sesuai urutan dibuat
+func (l *Lexicon) List() []LexiconEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]LexiconEntry{}, l.entries...)
+}
+
+// Get mengembalikan salinan satu entri
+func (l *Lexicon) Get(id string) (*LexiconEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	index := l.indexLocked(id)
+	if index < 0 {
+		return nil, ErrLexiconNotFound
+	}
+	entry := l.entries[index]
+	return &entry, nil
+}
+
+// Create memvalidasi lalu menyimpan entri baru
+func (l *Lexicon) Create(entry LexiconEntry) (*LexiconEntry, error) {
+	entry, re, err := prepareLexiconEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) >= MaxLexiconEntries {
+		return nil, fmt.Errorf("%w: lexicon is full (max %d entries)", ErrInvalidLexicon, MaxLexiconEntries)
+	}
+	now := time.Now()
+	entry.ID = newID()
+	entry.CreatedAt, entry.UpdatedAt = now, now
+
+	entries := append(append([]LexiconEntry{}, l.entries...), entry)
+	if err := l.saveLocked(entries); err != nil {
+		return nil, err
+	}
+	l.compiled[entry.ID] = re
+	return &entry, nil
+}
+
+// Update mengganti isi entri; ID dan waktu dibuat dipertahankan
+func (l *Lexicon) Update(id string, entry LexiconEntry) (*LexiconEntry, error) {
+	entry, re, err := prepareLexiconEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	index := l.indexLocked(id)
+	if index < 0 {
+		return nil, ErrLexiconNotFound
+	}
+	entry.ID = id
+	entry.CreatedAt = l.entries[index].CreatedAt
+	entry.UpdatedAt = time.Now()
+
+	entries := append([]LexiconEntry{}, l.entries...)
+	entries[index] = entry
+	if err := l.saveLocked(entries); err != nil {
+		return nil, err
+	}
+	l.compiled[id] = re
+	return &entry, nil
+}
+
+// Delete menghapus entri
+func (l *Lexicon) Delete(id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	index := l.indexLocked(id)
+	if index < 0 {
+		return ErrLexiconNotFound
+	}
+	entries := append(append([]LexiconEntry{}, l.entries[:index]...), l.entries[index+1:]...)
+	if err := l.saveLocked(entries); err != nil {
+		return err
+	}
+	delete(l.compiled, id)
+	return nil
+}
+
+// Import menambahkan entri dari file impor. Entri dengan pola, cara cocok,
+// bahasa dan situs yang sama dengan entri lama menimpa bacaannya; jika replace
+// diisi, semua entri lama dihapus dulu. Semua entri divalidasi sebelum ada
+// yang disimpan.
+func (l *Lexicon) Import(imported []LexiconEntry, replace bool) (int, error) {
+	prepared := make([]LexiconEntry, 0, len(imported))
+	compiled := make([]*regexp.Regexp, 0, len(imported))
+	for i, entry := range imported {
+		entry, re, err := prepareLexiconEntry(entry)
+		if err != nil {
+			return 0, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		prepared = append(prepared, entry)
+		compiled = append(compiled, re)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := append([]LexiconEntry{}, l.entries...)
+	if replace {
+		entries = entries[:0]
+	}
+	existing := map[string]int{}
+	for i, entry := range entries {
+		existing[lexiconIdentity(entry)] = i
+	}
+
+	now := time.Now()
+	regexps := map[string]*regexp.Regexp{}
+	for i, entry := range prepared {
+		entry.UpdatedAt = now
+		if index, ok := existing[lexiconIdentity(entry)]; ok {
+			entry.ID = entries[index].ID
+			entry.CreatedAt = entries[index].CreatedAt
+			entries[index] = entry
+		} else {
+			entry.ID = newID()
+			entry.CreatedAt = now
+			existing[lexiconIdentity(entry)] = len(entries)
+			entries = append(entries, entry)
+		}
+		regexps[entry.ID] = compiled[i]
+	}
+	if len(entries) > MaxLexiconEntries {
+		return 0, fmt.Errorf("%w: lexicon is full (max %d entries)", ErrInvalidLexicon, MaxLexiconEntries)
+	}
+
+	if err := l.saveLocked(entries); err != nil {
+		return 0, err
+	}
+	if replace {
+		l.compiled = map[string]*regexp.Regexp{}
+	}
+	for id, re := range regexps {
+		l.compiled[id] = re
+	}
+	return len(prepared), nil
+}
+
+// rules menyusun aturan dari entri yang berlaku untuk bahasa dan situs.
+// Entri khusus situs dicoba sebelum entri khusus bahasa, lalu entri umum;
+// di tingkat yang sama pola yang lebih panjang lebih dulu.
+func (l *Lexicon) rules(language, site string) *lexiconRules {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	applicable := []LexiconEntry{}
+	for _, entry := range l.entries {
+		if entry.Applies(language, site) {
+			applicable = append(applicable, entry)
+		}
+	}
+	if len(applicable) == 0 {
+		return nil
+	}
+	specificity := func(entry LexiconEntry) int {
+		score := 0
+		if entry.Site != "" {
+			score += 2
+		}
+		if entry.Language != "" {
+			score++
+		}
+		return score
+	}
+	sort.SliceStable(applicable, func(i, j int) bool {
+		if a, b := specificity(applicable[i]), specificity(applicable[j]); a != b {
+			return a > b
+		}
+		return len(applicable[i].Pattern) > len(applicable[j].Pattern)
+	})
+
+	result := &lexiconRules{}
+	fingerprint := sha256.New()
+	for _, entry := range applicable {
+		result.rules = append(result.rules, lexiconRule(entry, l.compiled[entry.ID]))
+		fmt.Fprintf(fingerprint, "%s\x00%s\x00%s\x00%t\x00", entry.Pattern, entry.Replacement, entry.Match, entry.IgnoreCase)
+	}
+	result.key = hex.EncodeToString(fingerprint.Sum(nil))
+	return result
+}
+
+func (l *Lexicon) indexLocked(id string) int {
+	for i, entry := range l.entries {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// saveLocked menulis entries ke disk lalu memakainya sebagai isi leksikon
+func (l *Lexicon) saveLocked(entries []LexiconEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(l.path, data); err != nil {
+		return err
+	}
+	l.entries = entries
+	return nil
+}
+
+// lexiconRule mengubah entri menjadi aturan normalisasi
+func lexiconRule(entry LexiconEntry, re *regexp.Regexp) normalizeRule {
+	if entry.Match == LexiconRegex {
+		return normalizeRule{
+			pattern: re,
+			partial: true,
+			expand: func(groups []string) (string, bool) {
+				return expandGroups(re, entry.Replacement, groups), true
+			},
+		}
+	}
+	return normalizeRule{
+		pattern: re,
+		expand: func(groups []string) (string, bool) {
+			return entry.Replacement, true
+		},
+	}
+}
+
+// expandGroups mengisi $1 dan ${nama} di template dari grup match. Grup
+// disusun ulang menjadi satu teks supaya bisa memakai Regexp.ExpandString.
+func expandGroups(re *regexp.Regexp, template string, groups []string) string {
+	var src strings.Builder
+	match := make([]int, 0, 2*len(groups))
+	for _, group := range groups {
+		match = append(match, src.Len(), src.Len()+len(group))
+		src.WriteString(group)
+	}
+	return string(re.ExpandString(nil, template, src.String(), match))
+}
+
+// prepareLexiconEntry merapikan dan memvalidasi entri dari client
+func prepareLexiconEntry(entry LexiconEntry) (LexiconEntry, *regexp.Regexp, error) {
+	entry.Pattern = strings.TrimSpace(entry.Pattern)
+	entry.Replacement = strings.TrimSpace(entry.Replacement)
+	entry.Match = LexiconMatch(strings.ToLower(string(entry.Match)))
+	if entry.Match == "" {
+		entry.Match = LexiconWord
+	}
+	entry.Language = lexiconLanguage(entry.Language)
+	entry.Site = lexiconSite(entry.Site)
+
+	switch {
+	case entry.Pattern == "":
+		return entry, nil, fmt.Errorf("%w: pattern cannot be empty", ErrInvalidLexicon)
+	case utf8.RuneCountInString(entry.Pattern) > maxLexiconPattern:
+		return entry, nil, fmt.Errorf("%w: pattern too long (max %d characters)", ErrInvalidLexicon, maxLexiconPattern)
+	case utf8.RuneCountInString(entry.Replacement) > maxLexiconReplace:
+		return entry, nil, fmt.Errorf("%w: replacement too long (max %d characters)", ErrInvalidLexicon, maxLexiconReplace)
+	case entry.Match != LexiconWord && entry.Match != LexiconRegex:
+		return entry, nil, fmt.Errorf("%w: match must be %q or %q", ErrInvalidLexicon, LexiconWord, LexiconRegex)
+	}
+
+	re, err := compileLexiconEntry(entry)
+	if err != nil {
+		return entry, nil, fmt.Errorf("%w: %v", ErrInvalidLexicon, err)
+	}
+	return entry, re, nil
+}
+
+// compileLexiconEntry membuat regex entri. Pola kata cocok dengan spasi apa
+// saja di antara katanya; batas kata dicek oleh atWordBoundary.
+func compileLexiconEntry(entry LexiconEntry) (*regexp.Regexp, error) {
+	pattern := entry.Pattern
+	if entry.Match != LexiconRegex {
+		words := strings.Fields(pattern)
+		for i := range words {
+			words[i] = regexp.QuoteMeta(words[i])
+		}
+		pattern = strings.Join(words, `\s+`)
+	}
+	if entry.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// lexiconIdentity entri dianggap sama jika pola, cara cocok dan cakupannya sama
+func lexiconIdentity(entry LexiconEntry) string {
+	pattern := entry.Pattern
+	if entry.IgnoreCase {
+		pattern = strings.ToLower(pattern)
+	}
+	return strings.Join([]string{string(entry.Match), pattern, entry.Language, entry.Site}, "\x00")
+}
+
+// lexiconLanguage kode bahasa dasar: id-ID -> id
+func lexiconLanguage(language string) string {
+	return strings.ToLower(strings.TrimSpace(strings.SplitN(language, "-", 2)[0]))
+}
+
+// lexiconSite merapikan situs menjadi hostname tanpa www (URL lengkap boleh)
+func lexiconSite(site string) string {
+	site = strings.ToLower(strings.TrimSpace(site))
+	if strings.Contains(site, "://") {
+		if parsed, err := url.Parse(site); err == nil {
+			site = parsed.Hostname()
+		}
+	}
+	site = strings.SplitN(site, "/", 2)[0]
+	if host, _, found := strings.Cut(site, ":"); found {
+		site = host
+	}
+	return strings.TrimPrefix(site, "www.")
+}
+
+// ParseLexiconJSON membaca file impor JSON: array entri atau {"entries": [...]}
+func ParseLexiconJSON(data []byte) ([]LexiconEntry, error) {
+	entries := []LexiconEntry{}
+	if err := json.Unmarshal(data, &entries); err == nil {
+		return entries, nil
+	}
+	var wrapped struct {
+		Entries []LexiconEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLexicon, err)
+	}
+	return wrapped.Entries, nil
+}
+
+// ParseLexiconCSV membaca file impor CSV. Baris pertama adalah header dengan
+// nama kolom seperti lexiconCSVHeader; hanya pattern dan replacement yang wajib.
+func ParseLexiconCSV(r io.Reader) ([]LexiconEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLexicon, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["pattern"]; !ok {
+		return nil, fmt.Errorf("%w: CSV header must include pattern and replacement", ErrInvalidLexicon)
+	}
+	if _, ok := columns["replacement"]; !ok {
+		return nil, fmt.Errorf("%w: CSV header must include pattern and replacement", ErrInvalidLexicon)
+	}
+
+	entries := []LexiconEntry{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidLexicon, err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := LexiconEntry{
+			Pattern:     field("pattern"),
+			Replacement: field("replacement"),
+			Match:       LexiconMatch(field("match")),
+			Language:    field("language"),
+			Site:        field("site"),
+		}
+		if value := field("ignore_case"); value != "" {
+			if entry.IgnoreCase, err = strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("%w: line %d: ignore_case must be true or false", ErrInvalidLexicon, line)
+			}
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// WriteLexiconCSV menulis entri dengan kolom lexiconCSVHeader
+func WriteLexiconCSV(w io.Writer, entries []LexiconEntry) error {
+	writer := csv.NewWriter(w)
+	writer.Write(lexiconCSVHeader)
+	for _, entry := range entries {
+		writer.Write([]string{
+			entry.Pattern,
+			entry.Replacement,
+			string(entry.Match),
+			strconv.FormatBool(entry.IgnoreCase),
+			entry.Language,
+			entry.Site,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// LexiconTTSService membungkus TTSService sehingga teks yang diucapkan atau
+// dirender memakai entri leksikon yang berlaku untuk bahasa dan situsnya.
+// Dipasang di luar CachedTTSService, supaya perubahan leksikon mengubah key cache.
+type LexiconTTSService struct {
+	TTSService
+	lexicon *Lexicon
+}
+
+// NewLexiconTTSService membungkus service dengan leksikon
+func NewLexiconTTSService(service TTSService, lexicon *Lexicon) *LexiconTTSService {
+	return &LexiconTTSService{TTSService: service, lexicon: lexicon}
+}
+
+// Speak memutar teks dengan entri leksikon yang berlaku
+func (s *LexiconTTSService) Speak(text string, config TTSConfig) (*TTSResponse, error) {
+	return s.TTSService.Speak(text, s.apply(config))
+}
+
+// Synthesize merender teks dengan entri leksikon yang berlaku
+func (s *LexiconTTSService) Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error) {
+	return s.TTSService.Synthesize(ctx, text, s.apply(config))
+}
+
+// Unwrap mengembalikan service yang dibungkus
+func (s *LexiconTTSService) Unwrap() TTSService {
+	return s.TTSService
+}
+
+func (s *LexiconTTSService) apply(config TTSConfig) TTSConfig {
+	language := config.Language
+	if language == "" {
+		language = GetDefaultConfig().Language
+	}
+	config.lexicon = s.lexicon.rules(language, config.Site)
+	return config
+}
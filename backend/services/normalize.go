@@ -0,0 +1,512 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Normalisasi teks sebelum dikirim ke engine: angka, uang, tanggal, jam,
+// bilangan tingkat dan singkatan diubah menjadi kata yang diucapkan.
+//
+// Setiap aturan punya pola dan fungsi pembaca. Teks dipindai sekali dari kiri;
+// di setiap posisi aturan yang cocok paling awal dipakai, dan jika beberapa
+// aturan cocok di posisi yang sama, aturan yang lebih dulu di daftar menang
+// (mis. tanggal sebelum angka biasa). Hasilnya berupa span terhadap teks asli,
+// supaya mark kata tetap bisa mengacu ke teks yang dikirim client.
+
+// Cara membaca yang bisa diminta lewat config.read_as; kosong berarti otomatis
+const (
+	ReadAsDigits = "digits" // Semua angka dieja per digit dalam kelompok (OTP, nomor rekening)
+	ReadAsSpell  = "spell"  // Semua huruf dan angka dieja satu per satu (kode booking)
+)
+
+// normalizeRule satu tahap normalisasi. expand menerima grup regex (indeks 0
+// adalah seluruh match) dan boleh menolak match dengan mengembalikan false.
+type normalizeRule struct {
+	pattern *regexp.Regexp
+	expand  func(groups []string) (string, bool)
+	partial bool // Boleh cocok di tengah kata (regex dari leksikon)
+	group   int  // Grup yang diganti; 0 = seluruh match. Teks sebelumnya hanya konteks (kata kunci "OTP")
+}
+
+// normalizedSpan potongan teks asli (offset byte) beserta bacaannya
+type normalizedSpan struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// normalizerRules memilih aturan berdasarkan bahasa (en-US dan sejenisnya
+// memakai aturan Inggris, selain itu Indonesia)
+func normalizerRules(language string) []normalizeRule {
+	if strings.HasPrefix(strings.ToLower(language), "en") {
+		return englishRules
+	}
+	return indonesianRules
+}
+
+// normalizerReader pembaca angka untuk bahasa yang sama dengan normalizerRules
+func normalizerReader(language string) numberReader {
+	if strings.HasPrefix(strings.ToLower(language), "en") {
+		return englishReader
+	}
+	return indonesianReader
+}
+
+// normalizeText mengembalikan teks yang siap diucapkan engine
+func normalizeText(text string, config TTSConfig) string {
+	return applySpans(text, normalizeSpans(text, config))
+}
+
+// applySpans mengganti setiap span di text dengan bacaannya
+func applySpans(text string, spans []normalizedSpan) string {
+	if len(spans) == 0 {
+		return text
+	}
+	var result strings.Builder
+	last := 0
+	for _, span := range spans {
+		result.WriteString(text[last:span.Start])
+		result.WriteString(spanText(text, span))
+		last = span.End
+	}
+	result.WriteString(text[last:])
+	return result.String()
+}
+
+// spanText bacaan span, diberi spasi jika simbol yang diganti menempel ke
+// kata di sebelahnya ("Mantap👍" -> "Mantap jempol ke atas")
+func spanText(text string, span normalizedSpan) string {
+	first, _ := utf8.DecodeRuneInString(text[span.Start:])
+	before, _ := utf8.DecodeLastRuneInString(text[:span.Start])
+	last, _ := utf8.DecodeLastRuneInString(text[:span.End])
+	after, _ := utf8.DecodeRuneInString(text[span.End:])
+	padBefore := span.Start > 0 && !isWordRune(first) && isWordRune(before)
+	padAfter := span.End < len(text) && !isWordRune(last) && isWordRune(after)
+
+	if span.Text == "" {
+		if padBefore && padAfter {
+			return " "
+		}
+		return ""
+	}
+	spoken := span.Text
+	if padBefore {
+		spoken = " " + spoken
+	}
+	if padAfter {
+		spoken += " "
+	}
+	return spoken
+}
+
+// spokenRange bacaan untuk text[start:end]. Span yang dimulai di dalam rentang
+// dibaca utuh walau melewati end; bagian yang sudah tercakup span dari
+// rentang sebelumnya dilewati.
+func spokenRange(text string, spans []normalizedSpan, start, end int) string {
+	var result strings.Builder
+	pos := start
+	for _, span := range spans {
+		if span.End <= pos {
+			continue
+		}
+		if span.Start >= end {
+			break
+		}
+		if span.Start < pos {
+			pos = span.End
+			continue
+		}
+		result.WriteString(text[pos:span.Start])
+		result.WriteString(spanText(text, span))
+		pos = span.End
+	}
+	if pos < end {
+		result.WriteString(text[pos:end])
+	}
+	return result.String()
+}
+
+// ruleMatch match yang sudah diterima dari satu aturan
+type ruleMatch struct {
+	start, end int
+	text       string
+}
+
+// normalizeSpans memindai teks dan mengembalikan span yang perlu dibaca ulang,
+// terurut dan tidak tumpang tindih. Entri leksikon di config dicoba sebelum
+// aturan bawaan, dan bacaannya ikut dinormalisasi (angka dari "$1 kali" tetap dibaca).
+func normalizeSpans(text string, config TTSConfig) []normalizedSpan {
+	rules := normalizerRules(config.Language)
+	switch config.ReadAs {
+	case ReadAsSpell:
+		// Mode eja membaca setiap karakter apa adanya, tanpa leksikon
+		return scanRules(text, []normalizeRule{spellRule(normalizerReader(config.Language))}, 0, config)
+	case ReadAsDigits:
+		rules = append([]normalizeRule{digitsRule(normalizerReader(config.Language))}, rules...)
+	}
+	// Simbol, emoji, URL dan email dibaca sebelum aturan angka, supaya angka
+	// di dalam alamat tidak dibaca sebagai bilangan
+	rules = append(append([]normalizeRule{}, verbalizerFor(config)...), rules...)
+
+	lexiconRules := 0
+	if config.lexicon != nil {
+		lexiconRules = len(config.lexicon.rules)
+		rules = append(append([]normalizeRule{}, config.lexicon.rules...), rules...)
+	}
+	return scanRules(text, rules, lexiconRules, config)
+}
+
+// scanRules menjalankan pemindaian normalizeSpans dengan daftar aturan jadi;
+// bacaan dari lexiconRules aturan pertama dinormalisasi lagi
+func scanRules(text string, rules []normalizeRule, lexiconRules int, config TTSConfig) []normalizedSpan {
+	// Match berikutnya untuk setiap aturan disimpan, supaya setiap aturan
+	// hanya memindai teks sekali
+	next := make([]*ruleMatch, len(rules))
+	exhausted := make([]bool, len(rules))
+
+	spans := []normalizedSpan{}
+	pos := 0
+	for pos < len(text) {
+		best := -1
+		for i := range rules {
+			if exhausted[i] {
+				continue
+			}
+			if next[i] == nil || next[i].start < pos {
+				next[i] = findRuleMatch(rules[i], text, pos)
+				if next[i] == nil {
+					exhausted[i] = true
+					continue
+				}
+			}
+			if best < 0 || next[i].start < next[best].start {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		match := next[best]
+		spoken := match.text
+		if best < lexiconRules {
+			spoken = normalizeText(spoken, TTSConfig{Language: config.Language, ReadAs: config.ReadAs})
+		}
+		spans = append(spans, normalizedSpan{Start: match.start, End: match.end, Text: spoken})
+		pos = match.end
+	}
+	return spans
+}
+
+// findRuleMatch mencari match pertama yang diterima mulai dari posisi from
+func findRuleMatch(rule normalizeRule, text string, from int) *ruleMatch {
+	for from < len(text) {
+		loc := rule.pattern.FindStringSubmatchIndex(text[from:])
+		if loc == nil {
+			return nil
+		}
+		start, end := loc[0]+from, loc[1]+from
+		if rule.group > 0 {
+			if loc[2*rule.group] < 0 {
+				return nil
+			}
+			start, end = loc[2*rule.group]+from, loc[2*rule.group+1]+from
+		}
+
+		if end > start && (rule.partial || atWordBoundary(text, start, end)) {
+			groups := make([]string, len(loc)/2)
+			for g := range groups {
+				if loc[2*g] >= 0 {
+					groups[g] = text[loc[2*g]+from : loc[2*g+1]+from]
+				}
+			}
+			if spoken, ok := rule.expand(groups); ok {
+				return &ruleMatch{start: start, end: end, text: spoken}
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		from = start + size
+	}
+	return nil
+}
+
+// atWordBoundary menolak match yang menempel di tengah kata atau angka
+// (mis. "3" di "B3" atau "12" di "123"), karena pencarian dimulai dari
+// tengah teks sehingga \b di awal pola tidak bisa diandalkan
+func atWordBoundary(text string, start, end int) bool {
+	first, _ := utf8.DecodeRuneInString(text[start:])
+	before, _ := utf8.DecodeLastRuneInString(text[:start])
+	// Tanda minus yang menempel ke kata sebelumnya adalah tanda hubung (10-12)
+	if start > 0 && (isWordRune(first) || first == '-' || first == '−') && isWordRune(before) {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(text[:end])
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	if end < len(text) && isWordRune(last) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// numberReader cara menulis, membaca dan mengeja angka dalam satu bahasa
+type numberReader struct {
+	thousands string // Pemisah ribuan
+	decimal   string // Pemisah desimal
+	pattern   string // Regex angka, dengan pemisah ribuan dan desimal opsional
+	digits    []string
+	point     string // Kata untuk pemisah desimal
+	minus     string
+	plus      string   // Kata untuk "+" di depan nomor telepon internasional
+	letters   []string // Nama huruf a - z untuk mengeja kode
+	words     func(int64) string
+}
+
+// read membaca angka sesuai format bahasanya. Angka berawalan nol atau lebih
+// dari 9 digit tanpa pemisah (nomor telepon, kode) dieja per digit, begitu
+// juga angka di luar jangkauan int64 yang tidak punya nama besaran.
+func (n numberReader) read(number string) string {
+	whole, fraction, _ := strings.Cut(number, n.decimal)
+	plain := !strings.Contains(whole, n.thousands) && fraction == ""
+	whole = strings.ReplaceAll(whole, n.thousands, "")
+	if plain && len(whole) > 1 && (whole[0] == '0' || len(whole) > 9) {
+		return n.readDigits(whole)
+	}
+
+	value, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return n.readDigits(whole)
+	}
+	spoken := n.words(value)
+	if fraction != "" {
+		spoken += " " + n.point + " " + n.spell(fraction)
+	}
+	return spoken
+}
+
+// spell mengeja angka satu per satu
+func (n numberReader) spell(digits string) string {
+	parts := []string{}
+	for _, r := range digits {
+		if r >= '0' && r <= '9' {
+			parts = append(parts, n.digits[r-'0'])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// readDigits mengeja nomor per digit dengan jeda (koma) di antara kelompok.
+// Kelompok yang ditulis (0812-3456-7890) dipertahankan; deretan digit yang
+// panjang dipecah, mis. OTP 482913 -> "empat delapan dua, sembilan satu tiga".
+func (n numberReader) readDigits(number string) string {
+	groups := []string{}
+	for _, run := range strings.FieldsFunc(number, func(r rune) bool { return r < '0' || r > '9' }) {
+		words := make([]string, 0, len(run))
+		for _, r := range run {
+			words = append(words, n.digits[r-'0'])
+		}
+		groups = append(groups, groupWords(words)...)
+	}
+	spoken := strings.Join(groups, ", ")
+	if strings.HasPrefix(strings.TrimSpace(number), "+") {
+		spoken = n.plus + " " + spoken
+	}
+	return spoken
+}
+
+// spellCode mengeja kode huruf per huruf dan angka per digit, berkelompok
+// seperti readDigits (KX7B2Q -> "ka eks tujuh, be dua ki")
+func (n numberReader) spellCode(code string) string {
+	groups := []string{}
+	for _, run := range strings.FieldsFunc(strings.ToLower(code), func(r rune) bool { return !isWordRune(r) }) {
+		words := []string{}
+		for _, r := range run {
+			if folded := foldLetter(r); folded != 0 {
+				r = folded
+			}
+			switch {
+			case r >= '0' && r <= '9':
+				words = append(words, n.digits[r-'0'])
+			case r >= 'a' && r <= 'z':
+				words = append(words, n.letters[r-'a'])
+			default:
+				words = append(words, string(r))
+			}
+		}
+		groups = append(groups, groupWords(words)...)
+	}
+	return strings.Join(groups, ", ")
+}
+
+// groupWords menggabungkan bacaan per karakter menjadi kelompok 3 (atau 4 jika
+// jumlahnya kelipatan 4), tanpa kelompok berisi satu karakter di akhir:
+// 6 -> 3+3, 7 -> 3+4, 10 -> 3+3+4, 12 -> 4+4+4
+func groupWords(words []string) []string {
+	size := 3
+	if len(words)%4 == 0 {
+		size = 4
+	}
+	groups := []string{}
+	for rest := words; len(rest) > 0; {
+		take := size
+		if len(rest) <= 4 {
+			take = len(rest)
+		}
+		groups = append(groups, strings.Join(rest[:take], " "))
+		rest = rest[take:]
+	}
+	return groups
+}
+
+// phoneRule membaca nomor telepon per digit; pattern mencocokkan format
+// nomor bahasa itu, dan jumlah digitnya harus 9 - 15
+func phoneRule(reader numberReader, pattern string) normalizeRule {
+	return normalizeRule{
+		pattern: regexp.MustCompile(pattern),
+		expand: func(g []string) (string, bool) {
+			count := 0
+			for _, r := range g[0] {
+				if r >= '0' && r <= '9' {
+					count++
+				}
+			}
+			if count < 9 || count > 15 {
+				return "", false
+			}
+			return reader.readDigits(g[0]), true
+		},
+	}
+}
+
+// digitCodeRule membaca deretan min - max digit per digit jika didahului salah
+// satu keywords (OTP, rekening, NIK) dalam jarak beberapa kata. Hanya angkanya
+// yang diganti; kata kuncinya tetap dinormalisasi aturan lain.
+func digitCodeRule(reader numberReader, keywords string, min, max int) normalizeRule {
+	return normalizeRule{
+		pattern: regexp.MustCompile(fmt.Sprintf(`(?i:\b(?:%s)\b)[^\d\n!?]{0,20}?\b(\d(?:[\s-]?\d){%d,%d})\b`, keywords, min-1, max-1)),
+		group:   1,
+		expand: func(g []string) (string, bool) {
+			return reader.readDigits(g[1]), true
+		},
+	}
+}
+
+// letterCodeRule mengeja kode huruf besar dan angka (5 - 10 karakter) setelah
+// salah satu keywords, mis. "kode booking: KX7B2Q"
+func letterCodeRule(reader numberReader, keywords string) normalizeRule {
+	return normalizeRule{
+		pattern: regexp.MustCompile(`(?i:\b(?:` + keywords + `)\b)[^\n!?]{0,20}?\b([A-Z0-9]{5,10})\b`),
+		group:   1,
+		expand: func(g []string) (string, bool) {
+			return reader.spellCode(g[1]), true
+		},
+	}
+}
+
+// digitsRule untuk read_as=digits: setiap angka, termasuk yang dipisah titik,
+// garis miring, tanda hubung atau spasi, dieja per digit
+func digitsRule(reader numberReader) normalizeRule {
+	return normalizeRule{
+		pattern: regexp.MustCompile(`\+?\d+(?:[\s./-]\d+)*`),
+		expand: func(g []string) (string, bool) {
+			return reader.readDigits(g[0]), true
+		},
+	}
+}
+
+// spellRule untuk read_as=spell: setiap kata (atau kode bertanda hubung)
+// dieja huruf per huruf, dengan jeda sebelum kata berikutnya
+func spellRule(reader numberReader) normalizeRule {
+	return normalizeRule{
+		pattern: regexp.MustCompile(`[\p{L}\p{N}]+(?:[-./][\p{L}\p{N}]+)*(\s+)?`),
+		expand: func(g []string) (string, bool) {
+			spoken := reader.spellCode(g[0])
+			if g[1] != "" {
+				spoken += ", "
+			}
+			return spoken, true
+		},
+	}
+}
+
+// isZero mengecek apakah angka bernilai nol (mis. sen ",00")
+func isZero(digits string) bool {
+	return strings.Trim(digits, "0") == ""
+}
+
+// groupInt membaca grup regex sebagai bilangan (0 jika kosong atau tidak valid)
+func groupInt(value string) int {
+	n, _ := strconv.Atoi(value)
+	return n
+}
+
+// monthPattern alternatif regex dari nama atau singkatan bulan, yang lebih
+// panjang dulu (sept sebelum sep)
+func monthPattern(months map[string]int) string {
+	names := make([]string, 0, len(months))
+	for name := range months {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	return strings.Join(names, "|")
+}
+
+// validDate mengecek tanggal dan bulan secara kasar (hari 1-31, bulan 1-12)
+func validDate(day, month int) bool {
+	return day >= 1 && day <= 31 && month >= 1 && month <= 12
+}
+
+// abbreviation satu singkatan beserta cara menangani titiknya
+type abbreviation struct {
+	expansion string
+	dot       int
+}
+
+const (
+	dotOptional = iota // Titik boleh ada dan ikut diganti (Jl. / Jl)
+	dotRequired        // Hanya dibaca jika diakhiri titik (Dr., Kab.), karena tanpa titik bisa berarti kata lain
+	dotKeep            // Titik dipertahankan karena bisa mengakhiri kalimat (dll.)
+)
+
+// abbreviationRule membuat aturan singkatan tunggal dari daftar (tidak
+// peka huruf besar/kecil kecuali yang ditulis dengan huruf besar semua)
+func abbreviationRule(list map[string]abbreviation) normalizeRule {
+	names := make([]string, 0, len(list))
+	for name := range list {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	// Nama yang lebih panjang dicoba lebih dulu (jln sebelum jl)
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	return normalizeRule{
+		pattern: regexp.MustCompile(`(?i)\b(` + strings.Join(names, "|") + `)\b(\.)?`),
+		expand: func(groups []string) (string, bool) {
+			entry, ok := list[strings.ToLower(groups[1])]
+			if !ok {
+				// Singkatan huruf besar (WIB, RT) harus ditulis persis
+				entry, ok = list[groups[1]]
+				if !ok {
+					return "", false
+				}
+			}
+			switch entry.dot {
+			case dotRequired:
+				if groups[2] == "" {
+					return "", false
+				}
+			case dotKeep:
+				return entry.expansion + groups[2], true
+			}
+			return entry.expansion, true
+		},
+	}
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// englishReader angka Inggris: koma untuk ribuan, titik untuk desimal
+var englishReader = numberReader{
+	thousands: ",",
+	decimal:   ".",
+	pattern:   `\d{1,3}(?:,\d{3})+(?:\.\d+)?|\d+(?:\.\d+)?`,
+	digits:    englishDigits,
+	point:     "point",
+	minus:     "minus",
+	plus:      "plus",
+	letters:   englishLetters,
+	words:     englishNumber,
+}
+
+var englishLetters = []string{"ay", "bee", "see", "dee", "ee", "ef", "jee", "aitch", "eye", "jay", "kay", "el", "em",
+	"en", "oh", "pee", "cue", "ar", "ess", "tee", "you", "vee", "double you", "ex", "why", "zee"}
+
+var englishDigits = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+
+var englishTeens = []string{"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen",
+	"sixteen", "seventeen", "eighteen", "nineteen"}
+
+var englishTens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+
+var englishMonths = []string{"", "January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December"}
+
+// englishMonthNames nama dan singkatan bulan untuk tanggal bertulis
+// (Oct 12, 2024, 12 Oct 2024, Sept. 3rd)
+var englishMonthNames = map[string]int{
+	"january": 1, "february": 2, "march": 3, "april": 4, "may": 5, "june": 6, "july": 7,
+	"august": 8, "september": 9, "october": 10, "november": 11, "december": 12,
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "jun": 6, "jul": 7,
+	"aug": 8, "sep": 9, "sept": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// englishCurrencies simbol mata uang: nama tunggal, jamak dan satuan sen
+var englishCurrencies = map[string][3]string{
+	"$": {"dollar", "dollars", "cent"}, "US$": {"US dollar", "US dollars", "cent"}, "USD": {"US dollar", "US dollars", "cent"},
+	"€": {"euro", "euros", "cent"}, "EUR": {"euro", "euros", "cent"}, "£": {"pound", "pounds", "penny"},
+	"Rp": {"rupiah", "rupiah", ""}, "IDR": {"rupiah", "rupiah", ""},
+}
+
+// englishUnits nama satuan tunggal; jamaknya ditambah "s" kecuali persen dan derajat
+var englishUnits = map[string]string{
+	"%": "percent", "°": "degrees", "°C": "degrees Celsius", "°F": "degrees Fahrenheit",
+	"km": "kilometer", "m": "meter", "cm": "centimeter", "mm": "millimeter",
+	"kg": "kilogram", "g": "gram", "mg": "milligram", "lb": "pound", "lbs": "pound",
+	"mi": "mile", "ft": "foot", "in": "inch", "l": "liter", "ml": "milliliter",
+}
+
+// englishAbbreviations singkatan umum dalam teks bahasa Inggris
+var englishAbbreviations = map[string]abbreviation{
+	"mr": {"mister", dotOptional}, "mrs": {"missus", dotOptional}, "ms": {"miz", dotRequired},
+	"prof": {"professor", dotRequired}, "jr": {"junior", dotRequired},
+	"sr": {"senior", dotRequired}, "no": {"number", dotRequired}, "vs": {"versus", dotOptional},
+	"etc": {"et cetera", dotKeep}, "approx": {"approximately", dotRequired}, "ave": {"avenue", dotRequired},
+	"rd": {"road", dotRequired}, "blvd": {"boulevard", dotRequired}, "dept": {"department", dotRequired},
+	"e.g": {"for example", dotOptional}, "i.e": {"that is", dotOptional},
+}
+
+// englishRules urutan aturan untuk bahasa Inggris (en-US)
+var englishRules = []normalizeRule{
+	// Kode booking dieja: confirmation code KX7B2Q
+	letterCodeRule(englishReader, `booking(?:\s+(?:code|reference|ref))?|confirmation(?:\s+(?:code|number))?|reference|ref|PNR|tracking(?:\s+number)?|voucher|promo\s+code|coupon`),
+	// OTP dan PIN dibaca per digit: your code is 482913
+	digitCodeRule(englishReader, `OTP|PIN|passcode|code|password`, 4, 8),
+	// Nomor rekening dan telepon: account number 1234567890
+	digitCodeRule(englishReader, `account|acct|routing|SSN|card|phone|tel|mobile|cell|fax|ID|number`, 6, 24),
+	// Nomor telepon: (555) 123-4567, 555-123-4567, +44 20 7946 0958
+	phoneRule(englishReader, `(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)\s?|\d{3}[\s.-])\d{3}[\s.-]\d{4}|\+\d{1,3}(?:[\s-]?\d{2,4}){2,5}`),
+	// Tanggal ISO: 2025-08-12
+	{
+		pattern: regexp.MustCompile(`(\d{4})-(\d{1,2})-(\d{1,2})`),
+		expand: func(g []string) (string, bool) {
+			return englishDate(groupInt(g[2]), groupInt(g[3]), groupInt(g[1]))
+		},
+	},
+	// Tanggal format AS: 08/12/2025 (bulan lebih dulu)
+	{
+		pattern: regexp.MustCompile(`(\d{1,2})/(\d{1,2})/(\d{4})`),
+		expand: func(g []string) (string, bool) {
+			return englishDate(groupInt(g[1]), groupInt(g[2]), groupInt(g[3]))
+		},
+	},
+	// Tanggal bertulis: Oct 12, 2024, October 12th, Sept. 3
+	{
+		pattern: regexp.MustCompile(`((?i:` + monthPattern(englishMonthNames) + `))\b\.?\s+(\d{1,2})(?:st|nd|rd|th)?\b(?:,?\s+(\d{4})\b)?`),
+		expand: func(g []string) (string, bool) {
+			// Nama bulan ditulis kapital; "may 5" bisa kata kerja
+			if !unicode.IsUpper(rune(g[1][0])) {
+				return "", false
+			}
+			return englishMonthDate(englishMonthNames[strings.ToLower(g[1])], groupInt(g[2]), g[3])
+		},
+	},
+	// Tanggal bertulis dengan hari lebih dulu: 12 Oct 2024, 3rd May
+	{
+		pattern: regexp.MustCompile(`(\d{1,2})(?:st|nd|rd|th)?\s+((?i:` + monthPattern(englishMonthNames) + `))\b(?:\.?,?\s+(\d{4})\b)?`),
+		expand: func(g []string) (string, bool) {
+			if !unicode.IsUpper(rune(g[2][0])) {
+				return "", false
+			}
+			return englishMonthDate(englishMonthNames[strings.ToLower(g[2])], groupInt(g[1]), g[3])
+		},
+	},
+	// Uang: $1,250.50, € 20, $5 million
+	{
+		pattern: regexp.MustCompile(`(US\$|\$|USD|EUR|€|£|Rp|IDR)\s?(` + englishReader.pattern + `)(?:\s?(thousand|million|billion|trillion)\b)?`),
+		expand: func(g []string) (string, bool) {
+			return englishMoney(g[2], g[3], englishCurrencies[g[1]]), true
+		},
+	},
+	// Jam: 8:30, 8:05 pm, 5pm
+	{
+		pattern: regexp.MustCompile(`(\d{1,2})(?::([0-5]\d))?(?:\s?([AaPp])\.?[Mm]\b)?`),
+		expand: func(g []string) (string, bool) {
+			hour := groupInt(g[1])
+			meridiem := strings.ToLower(g[3])
+			if hour > 23 || (g[2] == "" && meridiem == "") || (meridiem != "" && (hour == 0 || hour > 12)) {
+				return "", false
+			}
+
+			parts := []string{englishNumber(int64(hour))}
+			switch minute := groupInt(g[2]); {
+			case g[2] == "":
+			case minute == 0 && meridiem == "":
+				parts = append(parts, "o'clock")
+			case minute == 0:
+			case minute < 10:
+				parts = append(parts, "oh", englishNumber(int64(minute)))
+			default:
+				parts = append(parts, englishNumber(int64(minute)))
+			}
+			if meridiem != "" {
+				parts = append(parts, meridiem, "m")
+			}
+			return strings.Join(parts, " "), true
+		},
+	},
+	// Bilangan tingkat: 1st, 22nd, 103rd
+	{
+		pattern: regexp.MustCompile(`(\d{1,6})(?:st|nd|rd|th)`),
+		expand: func(g []string) (string, bool) {
+			return englishOrdinal(int64(groupInt(g[1]))), true
+		},
+	},
+	// Angka, dengan tanda minus dan satuan: 1,250, 3.5 kg, -2, 50%, 1998
+	{
+		pattern: regexp.MustCompile(`([-−])?(` + englishReader.pattern + `)(?:\s?(%|°[CF]?|(?:km|kg|cm|mm|ml|mg|lbs|lb|mi|ft|in|g|m|l)\b))?`),
+		expand: func(g []string) (string, bool) {
+			number, unit := g[2], g[3]
+
+			// Angka empat digit tanpa satuan biasanya tahun
+			var spoken string
+			if year := groupInt(number); g[1] == "" && unit == "" && len(number) == 4 && year >= 1100 && year < 2100 {
+				spoken = englishYear(year)
+			} else {
+				spoken = englishReader.read(number)
+			}
+
+			if g[1] != "" {
+				spoken = englishReader.minus + " " + spoken
+			}
+			if unit != "" {
+				name := englishUnits[unit]
+				if number != "1" && !strings.HasPrefix(name, "degree") && name != "percent" {
+					name = englishPlural(name)
+				}
+				spoken += " " + name
+			}
+			return spoken, true
+		},
+	},
+	// St. dan Dr. sebelum nama adalah gelar (St. Louis, Dr. Smith); setelah
+	// nama jalan atau tanpa nama sesudahnya berarti Street dan Drive
+	// (123 Main St. Apt 4, Elm Dr). Titik jalan dipertahankan karena bisa
+	// mengakhiri kalimat.
+	{
+		pattern: regexp.MustCompile(`(\d+\s+(?:\p{Lu}[\p{L}'-]*\s+)+)?\b((St|Dr)(\.)?)(\s+\p{Lu})?`),
+		group:   2,
+		expand: func(g []string) (string, bool) {
+			title := map[string]string{"St": "Saint", "Dr": "Doctor"}
+			street := map[string]string{"St": "Street", "Dr": "Drive"}
+			if g[5] != "" && g[1] == "" {
+				return title[g[3]], true
+			}
+			return street[g[3]] + g[4], true
+		},
+	},
+	abbreviationRule(englishAbbreviations),
+}
+
+// englishMonthDate membaca tanggal bertulis; tahun boleh kosong ("October twelfth")
+func englishMonthDate(month, day int, year string) (string, bool) {
+	if year != "" {
+		return englishDate(month, day, groupInt(year))
+	}
+	if !validDate(day, month) {
+		return "", false
+	}
+	return englishMonths[month] + " " + englishOrdinal(int64(day)), true
+}
+
+// englishDate membaca tanggal, mis. "August twelfth, twenty twenty-five"
+func englishDate(month, day, year int) (string, bool) {
+	if !validDate(day, month) {
+		return "", false
+	}
+	return englishMonths[month] + " " + englishOrdinal(int64(day)) + ", " + englishYear(year), true
+}
+
+// englishYear membaca tahun per dua digit (nineteen ninety-eight), kecuali 2000-2009
+func englishYear(year int) string {
+	high, low := year/100, year%100
+	switch {
+	case year >= 2000 && year < 2010:
+		return englishNumber(int64(year))
+	case low == 0:
+		return englishNumber(int64(high)) + " hundred"
+	case low < 10:
+		return englishNumber(int64(high)) + " oh " + englishNumber(int64(low))
+	}
+	return englishNumber(int64(high)) + " " + englishNumber(int64(low))
+}
+
+// englishMoney membaca nominal beserta mata uangnya; sen dibaca terpisah
+func englishMoney(number, scale string, currency [3]string) string {
+	whole, fraction, _ := strings.Cut(number, ".")
+	if scale != "" {
+		return englishReader.read(number) + " " + scale + " " + currency[1]
+	}
+
+	name := currency[1]
+	if whole == "1" {
+		name = currency[0]
+	}
+	spoken := englishReader.read(whole) + " " + name
+	if fraction != "" && !isZero(fraction) {
+		if currency[2] == "" || len(fraction) != 2 {
+			return englishReader.read(number) + " " + currency[1]
+		}
+		cents := groupInt(fraction)
+		unit := currency[2]
+		if cents != 1 {
+			unit = englishPlural(unit)
+		}
+		spoken += " and " + englishNumber(int64(cents)) + " " + unit
+	}
+	return spoken
+}
+
+// englishPlural bentuk jamak nama satuan dan mata uang di atas
+func englishPlural(word string) string {
+	switch {
+	case word == "foot":
+		return "feet"
+	case word == "penny":
+		return "pence"
+	case strings.HasSuffix(word, "inch"):
+		return word + "es"
+	}
+	return word + "s"
+}
+
+// englishOrdinal membaca bilangan tingkat: 1 -> "first", 22 -> "twenty-second"
+func englishOrdinal(n int64) string {
+	words := englishNumber(n)
+	split := strings.LastIndexAny(words, " -") + 1
+	last := words[split:]
+
+	irregular := map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+	switch {
+	case irregular[last] != "":
+		last = irregular[last]
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	return words[:split] + last
+}
+
+// englishNumber membaca bilangan bulat dalam bahasa Inggris
+func englishNumber(n int64) string {
+	if n == 0 {
+		return "zero"
+	}
+	if n < 0 {
+		return "minus " + englishNumber(-n)
+	}
+
+	parts := []string{}
+	for _, scale := range []struct {
+		value int64
+		name  string
+	}{{1e18, "quintillion"}, {1e15, "quadrillion"}, {1e12, "trillion"}, {1e9, "billion"}, {1e6, "million"}, {1e3, "thousand"}} {
+		if count := n / scale.value; count > 0 {
+			parts = append(parts, englishHundreds(count)+" "+scale.name)
+		}
+		n %= scale.value
+	}
+	if n > 0 {
+		parts = append(parts, englishHundreds(n))
+	}
+	return strings.Join(parts, " ")
+}
+
+// englishHundreds membaca 1 - 999
+func englishHundreds(n int64) string {
+	parts := []string{}
+	if n >= 100 {
+		parts = append(parts, englishDigits[n/100]+" hundred")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 10:
+		parts = append(parts, englishDigits[n])
+	case n < 20:
+		parts = append(parts, englishTeens[n-10])
+	case n%10 == 0:
+		parts = append(parts, englishTens[n/10])
+	default:
+		parts = append(parts, englishTens[n/10]+"-"+englishDigits[n%10])
+	}
+	return strings.Join(parts, " ")
+}
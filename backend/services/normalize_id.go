@@ -0,0 +1,366 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+// indonesianReader angka Indonesia: titik untuk ribuan, koma untuk desimal
+var indonesianReader = numberReader{
+	thousands: ".",
+	decimal:   ",",
+	pattern:   `\d{1,3}(?:\.\d{3})+(?:,\d+)?|\d+(?:,\d+)?`,
+	digits:    digitWords,
+	point:     "koma",
+	minus:     "minus",
+	plus:      "plus",
+	letters:   indonesianLetters,
+	words:     indonesianNumber,
+}
+
+var indonesianLetters = []string{"a", "be", "ce", "de", "e", "ef", "ge", "ha", "i", "je", "ka", "el", "em",
+	"en", "o", "pe", "ki", "er", "es", "te", "u", "ve", "we", "eks", "ye", "zet"}
+
+var indonesianMonths = []string{"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// indonesianMonthAbbreviations singkatan bulan (12 Okt 2024, 17 Agu); ejaan
+// Inggris ikut dikenali karena sering muncul di tiket dan struk
+var indonesianMonthAbbreviations = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "mei": 5, "jun": 6, "jul": 7,
+	"agu": 8, "agt": 8, "ags": 8, "agst": 8, "agus": 8, "aug": 8,
+	"sep": 9, "sept": 9, "okt": 10, "oct": 10, "nov": 11, "des": 12, "dec": 12,
+}
+
+// indonesianScales kata besaran setelah angka (Rp 5 jt, Rp 2 M)
+var indonesianScales = map[string]string{
+	"ribu": "ribu", "rb": "ribu", "juta": "juta", "jt": "juta",
+	"miliar": "miliar", "m": "miliar", "triliun": "triliun", "t": "triliun",
+}
+
+// indonesianCurrencies simbol mata uang asing yang ditulis sebelum angka
+var indonesianCurrencies = map[string]string{
+	"$": "dolar", "US$": "dolar Amerika", "USD": "dolar Amerika", "S$": "dolar Singapura",
+	"€": "euro", "EUR": "euro", "£": "pound", "RM": "ringgit", "¥": "yen",
+}
+
+var indonesianUnits = map[string]string{
+	"%": "persen", "°": "derajat", "°C": "derajat Celsius", "°F": "derajat Fahrenheit",
+	"km": "kilometer", "m": "meter", "cm": "sentimeter", "mm": "milimeter",
+	"kg": "kilogram", "g": "gram", "gr": "gram", "mg": "miligram",
+	"l": "liter", "ml": "mililiter",
+}
+
+var indonesianTimeZones = map[string]string{
+	"WIB": "waktu Indonesia barat", "WITA": "waktu Indonesia tengah", "WIT": "waktu Indonesia timur",
+}
+
+// indonesianAbbreviations singkatan umum di alamat, surat dan pesan singkat
+var indonesianAbbreviations = map[string]abbreviation{
+	"jl": {"jalan", dotOptional}, "jln": {"jalan", dotOptional}, "gg": {"gang", dotRequired},
+	"kel": {"kelurahan", dotRequired}, "kec": {"kecamatan", dotRequired}, "kab": {"kabupaten", dotRequired},
+	"prov": {"provinsi", dotRequired}, "kp": {"kampung", dotRequired}, "ds": {"desa", dotRequired},
+	"tgl": {"tanggal", dotOptional}, "hlm": {"halaman", dotRequired}, "no": {"nomor", dotRequired},
+	"bpk": {"bapak", dotOptional}, "bp": {"bapak", dotRequired},
+	"sdr": {"saudara", dotRequired}, "sdri": {"saudari", dotRequired}, "yth": {"yang terhormat", dotOptional},
+	"dr": {"dokter", dotRequired}, "drs": {"doktorandus", dotRequired}, "dra": {"doktoranda", dotRequired},
+	"prof": {"profesor", dotRequired}, "ir": {"insinyur", dotRequired}, "hj": {"hajah", dotRequired},
+	"dll": {"dan lain-lain", dotKeep}, "dsb": {"dan sebagainya", dotKeep}, "dst": {"dan seterusnya", dotKeep},
+	"dkk": {"dan kawan-kawan", dotKeep}, "tsb": {"tersebut", dotKeep},
+	"yg": {"yang", dotOptional}, "dgn": {"dengan", dotOptional}, "utk": {"untuk", dotOptional},
+	"tdk": {"tidak", dotOptional}, "sdh": {"sudah", dotOptional}, "blm": {"belum", dotOptional},
+	"krn": {"karena", dotOptional}, "spt": {"seperti", dotOptional}, "jg": {"juga", dotOptional},
+	"pd": {"pada", dotOptional}, "thd": {"terhadap", dotOptional}, "sbg": {"sebagai", dotOptional},
+	"kpd": {"kepada", dotOptional}, "dlm": {"dalam", dotOptional}, "bbrp": {"beberapa", dotOptional},
+	"s.d": {"sampai dengan", dotOptional}, "s/d": {"sampai dengan", dotOptional},
+	"a.n": {"atas nama", dotOptional}, "u.p": {"untuk perhatian", dotOptional},
+	"WIB": {"waktu Indonesia barat", dotOptional}, "WITA": {"waktu Indonesia tengah", dotOptional},
+	"WIT": {"waktu Indonesia timur", dotOptional}, "RT": {"er te", dotOptional}, "RW": {"er we", dotOptional},
+	"rek": {"rekening", dotRequired}, "telp": {"telepon", dotOptional}, "HP": {"ha pe", dotOptional},
+	"NIK": {"en i ka", dotOptional}, "OTP": {"o te pe", dotOptional}, "WA": {"we a", dotOptional},
+}
+
+// indonesianUnitPattern regex satuan setelah angka (kunci indonesianUnits)
+const indonesianUnitPattern = `%|°[CF]?|(?:km|kg|cm|mm|ml|mg|gr|g|m|l)\b`
+
+// indonesianSectionWords kata sebelum nomor versi atau bagian, yang titiknya
+// dibaca "titik" dan bukan desimal
+const indonesianSectionWords = `versi|ver|v|pasal|ayat|bab|subbab|bagian|butir|poin|tabel|gambar|lampiran|android|ios|windows`
+
+// indonesianWholeNumber angka yang seluruhnya berformat ribuan Indonesia (1.250.000)
+var indonesianWholeNumber = regexp.MustCompile(`^(?:` + indonesianReader.pattern + `)$`)
+
+// indonesianRules urutan aturan untuk bahasa Indonesia
+var indonesianRules = []normalizeRule{
+	// Kode booking dan resi dieja: kode booking KX7B2Q
+	letterCodeRule(indonesianReader, `kode\s+(?:booking|pemesanan|reservasi|tiket|voucher|promo|referensi)|booking|PNR|resi|referensi`),
+	// OTP dan PIN dibaca per digit: kode OTP Anda 482913
+	digitCodeRule(indonesianReader, `OTP|PIN|kode|token|sandi`, 4, 8),
+	// Nomor rekening, NIK dan nomor HP: rekening 1234 5678 90, NIK 3201...
+	digitCodeRule(indonesianReader, `rekening|rek|virtual\s+account|VA|NIK|NPWP|KTP|KK|HP|telp|telepon|ponsel|WA|WhatsApp|nomor`, 6, 24),
+	// Nomor telepon: 0812-3456-7890, +62 812 3456 7890, (021) 555-1234
+	phoneRule(indonesianReader, `(?:\+\d{1,3}[\s-]?|\(0\d{1,4}\)\s?|0)\d{2,4}(?:[\s-]?\d{2,5}){1,4}`),
+	// Tanggal ISO: 2025-08-12
+	{
+		pattern: regexp.MustCompile(`(\d{4})-(\d{1,2})-(\d{1,2})`),
+		expand: func(g []string) (string, bool) {
+			return indonesianDate(groupInt(g[3]), groupInt(g[2]), g[1])
+		},
+	},
+	// Tanggal: 12/08/2025, 12-08-2025, 12.08.2025
+	{
+		pattern: regexp.MustCompile(`(\d{1,2})([/.-])(\d{1,2})([/.-])(\d{4})`),
+		expand: func(g []string) (string, bool) {
+			if g[2] != g[4] {
+				return "", false
+			}
+			return indonesianDate(groupInt(g[1]), groupInt(g[3]), g[5])
+		},
+	},
+	// Tanggal dengan singkatan bulan: 12 Okt 2024, 17 Agu
+	{
+		pattern: regexp.MustCompile(`(\d{1,2})\s+((?i:` + monthPattern(indonesianMonthAbbreviations) + `))\b(?:\.?\s+(\d{4})\b)?`),
+		expand: func(g []string) (string, bool) {
+			month := indonesianMonthAbbreviations[strings.ToLower(g[2])]
+			if g[3] == "" {
+				if !validDate(groupInt(g[1]), month) {
+					return "", false
+				}
+				return indonesianNumber(int64(groupInt(g[1]))) + " " + indonesianMonths[month], true
+			}
+			return indonesianDate(groupInt(g[1]), month, g[3])
+		},
+	},
+	// Rupiah: Rp 1.250.000, Rp1.500,-, Rp 5 jt
+	{
+		pattern: regexp.MustCompile(`(?:[Rr][Pp]\.?|IDR)\s?(` + indonesianReader.pattern + `)(?:\s?((?i:ribu|rb|juta|jt|miliar|triliun)|M|T)\b)?(?:,-)?`),
+		expand: func(g []string) (string, bool) {
+			return indonesianMoney(g[1], g[2], "rupiah"), true
+		},
+	},
+	// Mata uang asing: $5, US$ 1.000, € 20
+	{
+		pattern: regexp.MustCompile(`(US\$|S\$|\$|USD|EUR|€|£|¥|RM)\s?(` + indonesianReader.pattern + `)(?:\s?((?i:ribu|rb|juta|jt|miliar|triliun)|M|T)\b)?`),
+		expand: func(g []string) (string, bool) {
+			return indonesianMoney(g[2], g[3], indonesianCurrencies[g[1]]), true
+		},
+	},
+	// Jam: 08.30, pukul 8.30, 14:00 WIB
+	{
+		pattern: regexp.MustCompile(`(?:([Pp]ukul|[Jj]am)\s+)?(\d{1,2})([.:])([0-5]\d)(?:[.:]([0-5]\d))?(?:\s?(WIB|WITA|WIT)\b)?`),
+		expand: func(g []string) (string, bool) {
+			hour := groupInt(g[2])
+			if hour > 24 {
+				return "", false
+			}
+			// Tanpa "pukul" atau zona waktu, "8.30" bisa jadi angka lain;
+			// hanya jam dua digit atau dengan titik dua yang dianggap jam
+			if g[1] == "" && g[6] == "" && len(g[2]) < 2 && g[3] != ":" {
+				return "", false
+			}
+
+			prefix := strings.ToLower(g[1])
+			if prefix == "" {
+				prefix = "pukul"
+			}
+			parts := []string{prefix, indonesianNumber(int64(hour))}
+			minute, second := groupInt(g[4]), groupInt(g[5])
+			switch {
+			case second > 0:
+				parts = append(parts, "lewat", indonesianNumber(int64(minute)), "menit", indonesianNumber(int64(second)), "detik")
+			case minute > 0 && minute < 10:
+				parts = append(parts, "lewat", indonesianNumber(int64(minute)))
+			case minute > 0:
+				parts = append(parts, indonesianNumber(int64(minute)))
+			}
+			if g[6] != "" {
+				parts = append(parts, indonesianTimeZones[g[6]])
+			}
+			return strings.Join(parts, " "), true
+		},
+	},
+	// Angka bertitik yang bukan ribuan. Satu titik dibaca sebagai desimal
+	// gaya Inggris (3.75 kg), kecuali setelah kata seperti versi atau pasal;
+	// beberapa titik dibaca "titik" (versi 2.10, pasal 3.1.2)
+	{
+		pattern: regexp.MustCompile(`(?i:\b(` + indonesianSectionWords + `)\.?\s*)?((\d+(?:\.\d+)+)(?:\s?(` + indonesianUnitPattern + `))?)`),
+		group:   2,
+		expand: func(g []string) (string, bool) {
+			number, unit := g[3], g[4]
+			if indonesianWholeNumber.MatchString(number) {
+				return "", false
+			}
+			parts := strings.Split(number, ".")
+			var spoken string
+			if g[1] == "" && len(parts) == 2 {
+				spoken = indonesianReader.read(parts[0]) + " " + indonesianReader.point + " " + indonesianReader.spell(parts[1])
+			} else {
+				words := []string{}
+				for _, part := range parts {
+					words = append(words, indonesianReader.read(part))
+				}
+				spoken = strings.Join(words, " titik ")
+			}
+			if unit != "" {
+				spoken += " " + indonesianUnits[unit]
+			}
+			return spoken, true
+		},
+	},
+	// RT 03/RW 05
+	{
+		pattern: regexp.MustCompile(`(RT|RW)\.?\s?(\d{1,3})(?:\s?/\s?(RT|RW)\.?\s?(\d{1,3}))?`),
+		expand: func(g []string) (string, bool) {
+			spoken := indonesianAbbreviations[g[1]].expansion + " " + indonesianNumber(int64(groupInt(g[2])))
+			if g[3] != "" {
+				spoken += " " + indonesianAbbreviations[g[3]].expansion + " " + indonesianNumber(int64(groupInt(g[4])))
+			}
+			return spoken, true
+		},
+	},
+	// Pecahan dan perbandingan: 1/2 -> setengah, 3/4 kg -> tiga perempat
+	// kilogram, 24/7 -> dua puluh empat per tujuh
+	{
+		pattern: regexp.MustCompile(`(\d{1,6})\s?/\s?(\d{1,6})(?:\s?(` + indonesianUnitPattern + `))?`),
+		expand: func(g []string) (string, bool) {
+			spoken := indonesianFraction(int64(groupInt(g[1])), int64(groupInt(g[2])))
+			if g[3] != "" {
+				spoken += " " + indonesianUnits[g[3]]
+			}
+			return spoken, true
+		},
+	},
+	// Nomor: No. 5, no 12
+	{
+		pattern: regexp.MustCompile(`(?:[Nn]o|NO)\.?\s?(\d+)`),
+		expand: func(g []string) (string, bool) {
+			return "nomor " + indonesianReader.read(g[1]), true
+		},
+	},
+	// Bilangan tingkat: ke-3, Ke-1
+	{
+		pattern: regexp.MustCompile(`([Kk])e-(\d{1,6})`),
+		expand: func(g []string) (string, bool) {
+			n := int64(groupInt(g[2]))
+			if n == 1 {
+				return "pertama", true
+			}
+			return strings.ToLower(g[1]) + "e" + indonesianNumber(n), true
+		},
+	},
+	// Angka, dengan tanda minus dan satuan: 1.250, 3,5 kg, -2, 50%
+	{
+		pattern: regexp.MustCompile(`([-−])?(` + indonesianReader.pattern + `)(?:\s?(` + indonesianUnitPattern + `))?`),
+		expand: func(g []string) (string, bool) {
+			spoken := indonesianReader.read(g[2])
+			if g[1] != "" {
+				spoken = indonesianReader.minus + " " + spoken
+			}
+			if g[3] != "" {
+				spoken += " " + indonesianUnits[g[3]]
+			}
+			return spoken, true
+		},
+	},
+	abbreviationRule(indonesianAbbreviations),
+}
+
+// indonesianFraction membaca pecahan biasa (penyebut 2 - 10, pembilang lebih
+// kecil) seperti "sepertiga" dan "dua perlima"; selain itu dibaca "x per y"
+func indonesianFraction(numerator, denominator int64) string {
+	if denominator < 2 || denominator > 10 || numerator < 1 || numerator >= denominator {
+		return indonesianNumber(numerator) + " per " + indonesianNumber(denominator)
+	}
+	if numerator == 1 && denominator == 2 {
+		return "setengah"
+	}
+	spoken := "per" + indonesianNumber(denominator)
+	if denominator == 4 {
+		spoken = "perempat"
+	}
+	if numerator == 1 {
+		return "se" + spoken
+	}
+	return indonesianNumber(numerator) + " " + spoken
+}
+
+// indonesianDate membaca tanggal, mis. "12 Agustus 2025" -> "dua belas Agustus dua ribu dua puluh lima"
+func indonesianDate(day, month int, year string) (string, bool) {
+	if !validDate(day, month) {
+		return "", false
+	}
+	return indonesianNumber(int64(day)) + " " + indonesianMonths[month] + " " + indonesianReader.read(year), true
+}
+
+// indonesianMoney membaca nominal uang; sen ",00" tidak dibaca
+func indonesianMoney(number, scale, currency string) string {
+	if whole, fraction, ok := strings.Cut(number, ","); ok && isZero(fraction) {
+		number = whole
+	}
+	spoken := indonesianReader.read(number)
+	if scale != "" {
+		spoken += " " + indonesianScales[strings.ToLower(scale)]
+	}
+	return spoken + " " + currency
+}
+
+// indonesianNumber membaca bilangan bulat dalam bahasa Indonesia
+func indonesianNumber(n int64) string {
+	if n == 0 {
+		return "nol"
+	}
+	if n < 0 {
+		return "minus " + indonesianNumber(-n)
+	}
+
+	parts := []string{}
+	for _, scale := range []struct {
+		value int64
+		name  string
+	}{{1e18, "kuintiliun"}, {1e15, "kuadriliun"}, {1e12, "triliun"}, {1e9, "miliar"}, {1e6, "juta"}, {1e3, "ribu"}} {
+		count := n / scale.value
+		n %= scale.value
+		switch {
+		case count == 0:
+		case count == 1 && scale.value == 1e3:
+			parts = append(parts, "seribu")
+		default:
+			parts = append(parts, indonesianHundreds(count)+" "+scale.name)
+		}
+	}
+	if n > 0 {
+		parts = append(parts, indonesianHundreds(n))
+	}
+	return strings.Join(parts, " ")
+}
+
+// indonesianHundreds membaca 1 - 999
+func indonesianHundreds(n int64) string {
+	parts := []string{}
+	switch hundreds := n / 100; {
+	case hundreds == 1:
+		parts = append(parts, "seratus")
+	case hundreds > 1:
+		parts = append(parts, digitWords[hundreds]+" ratus")
+	}
+
+	n %= 100
+	switch {
+	case n == 0:
+	case n < 10:
+		parts = append(parts, digitWords[n])
+	case n == 10:
+		parts = append(parts, "sepuluh")
+	case n == 11:
+		parts = append(parts, "sebelas")
+	case n < 20:
+		parts = append(parts, digitWords[n-10]+" belas")
+	default:
+		tens := digitWords[n/10] + " puluh"
+		if n%10 > 0 {
+			tens += " " + digitWords[n%10]
+		}
+		parts = append(parts, tens)
+	}
+	return strings.Join(parts, " ")
+}
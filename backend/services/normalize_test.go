@@ -0,0 +1,72 @@
+package services
+
+import "testing"
+
+func TestNormalizeIndonesian(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Rp2.000.000.000.000.000", "dua kuadriliun rupiah"},
+		{"Rp 1.500.000.000.000.000.000", "satu kuintiliun lima ratus kuadriliun rupiah"},
+		{"Rp 1.250.000", "satu juta dua ratus lima puluh ribu rupiah"},
+		{"12 Okt 2024", "dua belas Oktober dua ribu dua puluh empat"},
+		{"17 Agu", "tujuh belas Agustus"},
+		{"1 Sept. 2025", "satu September dua ribu dua puluh lima"},
+		{"3 des 2023", "tiga Desember dua ribu dua puluh tiga"},
+		{"12 Oktober 2024", "dua belas Oktober dua ribu dua puluh empat"},
+		{"32 Okt 2024", "tiga puluh dua Okt dua ribu dua puluh empat"},
+		{"Berat 3.75 kg", "Berat tiga koma tujuh lima kilogram"},
+		{"naik 2.5%", "naik dua koma lima persen"},
+		{"versi 2.10", "versi dua titik sepuluh"},
+		{"pasal 3.1.2", "pasal tiga titik satu titik dua"},
+		{"1.250 orang", "seribu dua ratus lima puluh orang"},
+		{"pukul 08.30", "pukul delapan tiga puluh"},
+		{"Dr. Budi", "dokter Budi"},
+		{"1/2", "setengah"},
+		{"minum 1/2 gelas", "minum setengah gelas"},
+		{"1/4 kg gula", "seperempat kilogram gula"},
+		{"3/4", "tiga perempat"},
+		{"1/3 bagian", "sepertiga bagian"},
+		{"2/5", "dua perlima"},
+		{"1 1/2 sendok", "satu setengah sendok"},
+		{"1/16", "satu per enam belas"},
+		{"buka 24/7", "buka dua puluh empat per tujuh"},
+		{"skor 50/50", "skor lima puluh per lima puluh"},
+		{"12/08/2025", "dua belas Agustus dua ribu dua puluh lima"},
+		{"RT 03/RW 05", "er te tiga er we lima"},
+		{"s/d", "sampai dengan"},
+	}
+	for _, tt := range tests {
+		if got := normalizeText(tt.text, TTSConfig{Language: "id-ID"}); got != tt.want {
+			t.Errorf("normalizeText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeEnglish(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"$2,000,000,000,000,000", "two quadrillion dollars"},
+		{"Oct 12, 2024", "October twelfth, twenty twenty-four"},
+		{"12 Oct 2024", "October twelfth, twenty twenty-four"},
+		{"Sept. 3rd", "September third"},
+		{"on the 3rd of May", "on the third of May"},
+		{"3rd May", "May third"},
+		{"you may 5 times", "you may five times"},
+		{"3.75 kg", "three point seven five kilograms"},
+		{"Dr. Smith lives on Elm Dr.", "Doctor Smith lives on Elm Drive."},
+		{"St. Louis", "Saint Louis"},
+		{"123 Main St. Apt 4", "one hundred twenty-three Main Street. Apt four"},
+		{"Meet at Main St", "Meet at Main Street"},
+		{"Dr Jones", "Doctor Jones"},
+		{"Strong coffee", "Strong coffee"},
+	}
+	for _, tt := range tests {
+		if got := normalizeText(tt.text, TTSConfig{Language: "en-US"}); got != tt.want {
+			t.Errorf("normalizeText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotSpeaking dikembalikan jika tidak ada speech yang bisa dikontrol
+	ErrNotSpeaking = errors.New("nothing is being spoken")
+	// ErrNotSupported dikembalikan jika operasi tidak didukung oleh service/OS
+	ErrNotSupported = errors.New("operation not supported by this TTS service")
+	// ErrNoSentence dikembalikan jika tidak ada kalimat berikutnya
+	ErrNoSentence = errors.New("no more sentences")
+)
+
+// Navigator diimplementasikan oleh TTSService yang memutar teks per kalimat
+type Navigator interface {
+	NextSentence() error
+	PreviousSentence() error
+	RepeatSentence() error
+}
+
+// findNavigator mencari Navigator di service atau service yang dibungkusnya
+// (mis. CachedTTSService di atas SystemTTSService)
+func findNavigator(service TTSService) (Navigator, bool) {
+	for service != nil {
+		if navigator, ok := service.(Navigator); ok {
+			return navigator, true
+		}
+		wrapper, ok := service.(interface{ Unwrap() TTSService })
+		if !ok {
+			break
+		}
+		service = wrapper.Unwrap()
+	}
+	return nil, false
+}
+
+// TTSStatus status utterance yang sedang diputar di host
+type TTSStatus struct {
+	Speaking            bool       `json:"speaking"`
+	Paused              bool       `json:"paused"`
+	Text                string     `json:"text,omitempty"`
+	Language            string     `json:"language,omitempty"`
+	StartedAt           *time.Time `json:"started_at,omitempty"`
+	ElapsedMs           float64    `json:"elapsed_ms"`
+	EstimatedDurationMs float64    `json:"estimated_duration_ms,omitempty"`
+	SentenceIndex       int        `json:"sentence_index"` // Kalimat yang sedang diputar (mulai 0)
+	SentenceCount       int        `json:"sentence_count"`
+	SentenceText        string     `json:"sentence_text,omitempty"`
+	SentenceStart       int        `json:"sentence_start"`      // Offset rune di Text, inklusif
+	SentenceEnd         int        `json:"sentence_end"`        // Offset rune di Text, eksklusif
+	Word                *Mark      `json:"word,omitempty"`      // Perkiraan kata yang sedang diucapkan
+	Progress            float64    `json:"progress"`            // 0.0 - 1.0 (perkiraan)
+	ClientID            string     `json:"client_id,omitempty"` // Pemilik audio
+	QueueLength         int        `json:"queue_length"`
+}
+
+// currentWord memperkirakan kata yang sedang diucapkan setelah elapsedMs
+func currentWord(segment Segment, elapsedMs, durationMs float64) *Mark {
+	words := estimateWordMarks(segment, 0, durationMs)
+	for i := range words {
+		if elapsedMs < words[i].TimeMs+words[i].DurationMs || i == len(words)-1 {
+			return &words[i]
+		}
+	}
+	return nil
+}
+
+// baseWordsPerMinute kecepatan bicara default engine sistem (say/espeak)
+const baseWordsPerMinute = 175
+
+// estimateSpeechMs memperkirakan lama bicara dari jumlah kata dan kecepatan
+func estimateSpeechMs(text string, speed float64) float64 {
+	if speed <= 0 {
+		speed = 1.0
+	}
+	words := len(strings.Fields(text))
+	return float64(words) / (baseWordsPerMinute * speed) * 60 * 1000
+}
+
+// estimateProgress membatasi progress ke 0.99 selama proses masih berjalan
+func estimateProgress(elapsedMs, estimatedMs float64) float64 {
+	if estimatedMs <= 0 || elapsedMs <= 0 {
+		return 0
+	}
+	progress := elapsedMs / estimatedMs
+	if progress > 0.99 {
+		progress = 0.99
+	}
+	return progress
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Batas waktu satu potongan dirender oleh worker
+	poolRenderTimeout = 30 * time.Second
+	// Render dianggap gagal jika output berhenti sebelum audio sentinel terlihat
+	poolIdleTimeout = 2 * time.Second
+	// Batas menunggu worker kosong sebelum kembali ke satu proses per request
+	poolAcquireTimeout = 2 * time.Second
+	// Worker yang menganggur dicek dengan probe sesering ini
+	poolHealthInterval = 30 * time.Second
+	// Output dianggap selesai jika tidak ada data baru selama ini (hanya saat start)
+	poolQuietPeriod  = 300 * time.Millisecond
+	poolStartTimeout = 5 * time.Second
+	// Jeda sebelum mencoba lagi worker yang gagal start
+	poolRetryDelay = 5 * time.Second
+)
+
+// sentinelSSML diucapkan setelah setiap request. Audionya dipelajari saat worker
+// start, sehingga akhir audio request bisa dikenali di stream stdout. Voice dan
+// prosody ditulis lengkap supaya audionya tidak terpengaruh request sebelumnya.
+const sentinelSSML = `<speak><voice name="en"><prosody rate="100%" volume="100%">lansia</prosody></voice></speak>`
+
+// probeSSML dipakai untuk health check (harus berbeda dari sentinel)
+const probeSSML = "<speak>tes</speak>"
+
+var (
+	// ErrPoolBusy dikembalikan jika tidak ada worker yang siap dalam batas waktu
+	ErrPoolBusy = errors.New("no engine worker available")
+	// ErrWorkerCrashed dikembalikan jika proses worker mati di tengah render
+	ErrWorkerCrashed = errors.New("engine worker exited")
+)
+
+// PoolStats statistik EnginePool
+type PoolStats struct {
+	Size     int   `json:"size"`
+	Ready    int   `json:"ready"`
+	Requests int64 `json:"requests"`
+	Failures int64 `json:"failures"`
+	Restarts int64 `json:"restarts"`
+}
+
+// EnginePool menjaga beberapa proses espeak yang hidup terus, supaya render
+// tidak perlu fork espeak baru untuk setiap request.
+//
+// Tiap worker menjalankan `espeak -m --stdout` tanpa teks, sehingga espeak membaca
+// SSML baris demi baris dari stdin dan menulis satu stream WAV ke stdout. Setelah
+// setiap baris request dikirim baris sentinel; audio sentinel yang sudah dikenal
+// menandai akhir audio request.
+type EnginePool struct {
+	command       []string
+	size          int
+	renderTimeout time.Duration
+	idleTimeout   time.Duration
+	idle          chan *poolWorker // Satu worker (hidup atau mati) per slot
+	done          chan struct{}
+
+	mu       sync.Mutex
+	ready    int
+	requests int64
+	failures int64
+	restarts int64
+}
+
+type poolWorker struct {
+	slot     int
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	output   chan []byte // Data dari stdout, ditutup saat proses selesai
+	format   wavFormat
+	sentinel []byte
+}
+
+// NewEnginePool menyiapkan size worker espeak. Worker dinyalakan di background;
+// sampai siap, Render mengembalikan ErrPoolBusy.
+func NewEnginePool(size int) (*EnginePool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("pool size must be positive")
+	}
+	engine, err := exec.LookPath("espeak")
+	if err != nil {
+		return nil, fmt.Errorf("espeak not found")
+	}
+	// stdout espeak ke pipe di-buffer penuh, stdbuf membuatnya langsung terkirim
+	stdbuf, err := exec.LookPath("stdbuf")
+	if err != nil {
+		return nil, fmt.Errorf("stdbuf not found")
+	}
+
+	return newEnginePool([]string{stdbuf, "-o0", engine, "-m", "--stdout"}, size), nil
+}
+
+// newEnginePool menyalakan size worker yang menjalankan command
+func newEnginePool(command []string, size int) *EnginePool {
+	p := &EnginePool{
+		command:       command,
+		size:          size,
+		renderTimeout: poolRenderTimeout,
+		idleTimeout:   poolIdleTimeout,
+		idle:          make(chan *poolWorker, size),
+		done:          make(chan struct{}),
+	}
+	for slot := 0; slot < size; slot++ {
+		go p.fill(slot)
+	}
+	go p.healthLoop()
+	return p
+}
+
+// Render merender satu potongan teks (sudah dibersihkan) menjadi WAV
+func (p *EnginePool) Render(text string, config TTSConfig) ([]byte, error) {
+	var worker *poolWorker
+	select {
+	case worker = <-p.idle:
+	case <-time.After(poolAcquireTimeout):
+		return nil, ErrPoolBusy
+	case <-p.done:
+		return nil, ErrPoolBusy
+	}
+
+	p.mu.Lock()
+	p.requests++
+	p.mu.Unlock()
+
+	audio, err := worker.render(espeakSSML(text, config), p.renderTimeout, p.idleTimeout)
+	if err != nil {
+		p.mu.Lock()
+		p.failures++
+		p.mu.Unlock()
+		p.replace(worker, err)
+		return nil, err
+	}
+	p.idle <- worker
+	return audio, nil
+}
+
+// Stats mengembalikan statistik pool
+func (p *EnginePool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PoolStats{
+		Size:     p.size,
+		Ready:    p.ready,
+		Requests: p.requests,
+		Failures: p.failures,
+		Restarts: p.restarts,
+	}
+}
+
+// Close mematikan semua worker
+func (p *EnginePool) Close() {
+	close(p.done)
+	for {
+		select {
+		case worker := <-p.idle:
+			worker.kill()
+		default:
+			return
+		}
+	}
+}
+
+// fill menyalakan worker untuk slot, mencoba lagi sampai berhasil
+func (p *EnginePool) fill(slot int) {
+	for {
+		worker, err := startPoolWorker(p.command, slot)
+		if err == nil {
+			p.mu.Lock()
+			p.ready++
+			p.mu.Unlock()
+			select {
+			case p.idle <- worker:
+			case <-p.done:
+				worker.kill()
+			}
+			return
+		}
+		log.Printf("Engine pool: worker %d failed to start: %v", slot, err)
+
+		select {
+		case <-time.After(poolRetryDelay):
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// replace mematikan worker yang bermasalah dan menyalakan penggantinya
+func (p *EnginePool) replace(worker *poolWorker, reason error) {
+	log.Printf("Engine pool: restarting worker %d: %v", worker.slot, reason)
+	worker.kill()
+
+	p.mu.Lock()
+	p.ready--
+	p.restarts++
+	p.mu.Unlock()
+
+	go p.fill(worker.slot)
+}
+
+// healthLoop mengecek worker yang menganggur dengan probe singkat
+func (p *EnginePool) healthLoop() {
+	ticker := time.NewTicker(poolHealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.done:
+			return
+		}
+
+		for i := 0; i < p.size; i++ {
+			var worker *poolWorker
+			select {
+			case worker = <-p.idle:
+			default:
+				continue // Sedang dipakai, berarti masih bekerja
+			}
+
+			if _, err := worker.render(probeSSML, poolStartTimeout, p.idleTimeout); err != nil {
+				p.replace(worker, fmt.Errorf("health check: %v", err))
+				continue
+			}
+			p.idle <- worker
+		}
+	}
+}
+
+func startPoolWorker(command []string, slot int) (*poolWorker, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	w := &poolWorker{
+		slot:   slot,
+		cmd:    cmd,
+		stdin:  stdin,
+		output: make(chan []byte, 64),
+	}
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := stdout.Read(buf)
+			if n > 0 {
+				w.output <- append([]byte(nil), buf[:n]...)
+			}
+			if err != nil {
+				break
+			}
+		}
+		close(w.output)
+		cmd.Wait()
+	}()
+
+	// Pelajari header WAV dan audio sentinel. Sentinel diucapkan dua kali
+	// untuk memastikan hasilnya selalu sama persis.
+	first, err := w.collect(sentinelSSML)
+	if err != nil {
+		w.kill()
+		return nil, err
+	}
+	format, pcm, err := parseWAV(first)
+	if err != nil {
+		w.kill()
+		return nil, fmt.Errorf("unexpected engine output: %v", err)
+	}
+	second, err := w.collect(sentinelSSML)
+	if err != nil {
+		w.kill()
+		return nil, err
+	}
+	if len(pcm) == 0 || !bytes.Equal(pcm, second) {
+		w.kill()
+		return nil, fmt.Errorf("engine output is not repeatable")
+	}
+
+	w.format = format
+	w.sentinel = second
+	return w, nil
+}
+
+// collect mengirim satu baris lalu membaca output sampai berhenti mengalir
+func (w *poolWorker) collect(line string) ([]byte, error) {
+	if _, err := io.WriteString(w.stdin, line+"\n"); err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	deadline := time.After(poolStartTimeout)
+	for {
+		quiet := time.After(poolQuietPeriod)
+		if len(buf) == 0 {
+			quiet = nil
+		}
+		select {
+		case data, ok := <-w.output:
+			if !ok {
+				return nil, ErrWorkerCrashed
+			}
+			buf = append(buf, data...)
+		case <-quiet:
+			return buf, nil
+		case <-deadline:
+			return nil, fmt.Errorf("engine did not answer")
+		}
+	}
+}
+
+// render mengirim request diikuti sentinel dan mengembalikan audio request sebagai WAV.
+// Jika output berhenti selama idle tanpa diakhiri audio sentinel, render gagal
+// (worker diganti dan pemanggil kembali ke satu proses per request).
+func (w *poolWorker) render(line string, timeout, idle time.Duration) ([]byte, error) {
+	if _, err := io.WriteString(w.stdin, line+"\n"+sentinelSSML+"\n"); err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	deadline := time.After(timeout)
+	for {
+		quiet := time.After(idle)
+		if len(buf) == 0 {
+			quiet = nil
+		}
+		select {
+		case data, ok := <-w.output:
+			if !ok {
+				return nil, ErrWorkerCrashed
+			}
+			buf = append(buf, data...)
+			if bytes.HasSuffix(buf, w.sentinel) {
+				return encodeWAV(w.format, buf[:len(buf)-len(w.sentinel)]), nil
+			}
+		case <-quiet:
+			return nil, fmt.Errorf("end of audio not found")
+		case <-deadline:
+			return nil, fmt.Errorf("render timed out")
+		}
+	}
+}
+
+func (w *poolWorker) kill() {
+	w.stdin.Close()
+	if w.cmd.Process != nil {
+		w.cmd.Process.Kill()
+	}
+	// Kosongkan output supaya goroutine pembaca bisa selesai
+	go func() {
+		for range w.output {
+		}
+	}()
+}
+
+// espeakSSML membungkus teks sebagai satu baris SSML dengan voice, speed dan volume dari config
+func espeakSSML(text string, config TTSConfig) string {
+	config = applyConfigDefaults(config)
+	escaped := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;").Replace(text)
+	return fmt.Sprintf(`<speak><voice name="%s"><prosody rate="%d%%" volume="%d%%">%s</prosody></voice></speak>`,
+		espeakVoice(config), int(config.Speed*100), int(config.Volume*100), escaped)
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeWorkerScript meniru `espeak -m --stdout`: header WAV sekali di awal
+// stream, lalu setiap baris SSML "diucapkan" sebagai byte barisnya sendiri.
+// Baris berisi crash mematikan proses, baris berisi hang berhenti di tengah.
+const fakeWorkerScript = `echo started >> "$1"
+header=0
+while IFS= read -r line; do
+	if [ "$header" = 0 ]; then
+		cat "$2"
+		header=1
+	fi
+	case "$line" in
+	*crash*) exit 1 ;;
+	*hang*) printf 'sebagian'; sleep 1; continue ;;
+	esac
+	printf '%s' "$line"
+done
+`
+
+// newFakePool membuat EnginePool dengan worker palsu dan mengembalikan
+// path log yang berisi satu baris per proses worker yang dinyalakan
+func newFakePool(t *testing.T, size int) (*EnginePool, string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "worker.sh")
+	if err := os.WriteFile(script, []byte(fakeWorkerScript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Seperti espeak yang menulis ke pipe, ukuran data chunk belum diketahui
+	header := testWAV(22050, 0)
+	binary.LittleEndian.PutUint32(header[len(header)-4:], 0xFFFFFFFF)
+	headerPath := filepath.Join(dir, "header.wav")
+	if err := os.WriteFile(headerPath, header, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	log := filepath.Join(dir, "starts.log")
+	p := newEnginePool([]string{"/bin/sh", script, log, headerPath}, size)
+	t.Cleanup(p.Close)
+	return p, log
+}
+
+// workerStarts menghitung berapa kali proses worker dinyalakan
+func workerStarts(t *testing.T, log string) int {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "started")
+}
+
+// renderPCM merender lewat pool dan memastikan hasilnya audio untuk teks itu
+func renderPCM(t *testing.T, p *EnginePool, text string) {
+	t.Helper()
+	config := TTSConfig{Language: "id-ID", Speed: 1.0, Volume: 1.0}
+	audio, err := p.Render(text, config)
+	if err != nil {
+		t.Fatalf("Render(%q): %v", text, err)
+	}
+	_, pcm, err := parseWAV(audio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := espeakSSML(text, config); !bytes.Equal(pcm, []byte(want)) {
+		t.Errorf("Render(%q) = %q, want %q", text, pcm, want)
+	}
+}
+
+func TestEnginePoolReusesWorker(t *testing.T) {
+	p, log := newFakePool(t, 1)
+	for _, text := range []string{"satu", "dua", "tiga"} {
+		renderPCM(t, p, text)
+	}
+
+	if starts := workerStarts(t, log); starts != 1 {
+		t.Errorf("worker started %d times, want 1", starts)
+	}
+	if stats := p.Stats(); stats.Requests != 3 || stats.Restarts != 0 || stats.Ready != 1 {
+		t.Errorf("stats = %+v, want 3 requests on one worker", stats)
+	}
+}
+
+func TestEnginePoolIdleTimeout(t *testing.T) {
+	p, log := newFakePool(t, 1)
+	p.idleTimeout = 100 * time.Millisecond
+
+	// Audio sentinel tidak pernah datang: render gagal cepat, bukan setelah renderTimeout
+	start := time.Now()
+	if _, err := p.Render("hang", TTSConfig{}); err == nil {
+		t.Fatal("Render succeeded without the sentinel")
+	}
+	if elapsed := time.Since(start); elapsed > poolAcquireTimeout+time.Second {
+		t.Errorf("Render took %v, want the idle timeout", elapsed)
+	}
+
+	// Worker diganti dan render berikutnya berjalan normal
+	renderPCM(t, p, "lagi")
+	if starts := workerStarts(t, log); starts != 2 {
+		t.Errorf("worker started %d times, want 2", starts)
+	}
+	if stats := p.Stats(); stats.Failures != 1 || stats.Restarts != 1 {
+		t.Errorf("stats = %+v, want one failure and one restart", stats)
+	}
+}
+
+func TestEnginePoolRestartsCrashedWorker(t *testing.T) {
+	p, log := newFakePool(t, 1)
+	renderPCM(t, p, "sebelum")
+
+	if _, err := p.Render("crash", TTSConfig{}); !errors.Is(err, ErrWorkerCrashed) {
+		t.Fatalf("Render(crash) error = %v, want ErrWorkerCrashed", err)
+	}
+
+	renderPCM(t, p, "sesudah")
+	if starts := workerStarts(t, log); starts != 2 {
+		t.Errorf("worker started %d times, want 2", starts)
+	}
+	if stats := p.Stats(); stats.Restarts != 1 || stats.Ready != 1 {
+		t.Errorf("stats = %+v, want one restart and a ready worker", stats)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package services
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup menjalankan command di process group sendiri, sehingga
+// pause/stop juga mengenai child process (mis. festival di balik sh -c)
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// suspendProcess menjeda seluruh process group
+func suspendProcess(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGSTOP)
+}
+
+// resumeProcess melanjutkan process group yang dijeda
+func resumeProcess(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGCONT)
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package services
+
+import "os/exec"
+
+// setProcessGroup tidak diperlukan di Windows
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// suspendProcess tidak didukung di Windows (tidak ada SIGSTOP)
+func suspendProcess(cmd *exec.Cmd) error {
+	return ErrNotSupported
+}
+
+// resumeProcess tidak didukung di Windows (tidak ada SIGCONT)
+func resumeProcess(cmd *exec.Cmd) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,426 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Priority menentukan perlakuan utterance baru terhadap yang sedang diputar
+type Priority string
+
+const (
+	// PriorityInterrupt menghentikan utterance yang sedang diputar dan langsung bicara
+	PriorityInterrupt Priority = "interrupt"
+	// PriorityQueue mengantre di belakang utterance lain
+	PriorityQueue Priority = "queue"
+	// PriorityDrop dibuang jika sedang ada yang bicara atau antre (drop-if-busy)
+	PriorityDrop Priority = "drop"
+)
+
+// Status hasil Enqueue
+const (
+	QueueStatePlaying = "playing"
+	QueueStateQueued  = "queued"
+	QueueStateDropped = "dropped"
+)
+
+// maxQueueLength batas utterance yang boleh menunggu di antrean
+const maxQueueLength = 50
+
+var (
+	// ErrNotOwner dikembalikan jika client mengontrol audio milik client lain
+	ErrNotOwner = errors.New("audio is owned by another client")
+	// ErrQueueFull dikembalikan jika antrean sudah penuh
+	ErrQueueFull = errors.New("speech queue is full")
+)
+
+// ParsePriority mengubah string dari request menjadi Priority
+func ParsePriority(value string) (Priority, error) {
+	switch Priority(strings.ToLower(strings.TrimSpace(value))) {
+	case "", PriorityInterrupt:
+		return PriorityInterrupt, nil
+	case PriorityQueue:
+		return PriorityQueue, nil
+	case PriorityDrop, "drop-if-busy":
+		return PriorityDrop, nil
+	}
+	return "", fmt.Errorf("unknown priority %q (use interrupt, queue or drop)", value)
+}
+
+// QueueResult hasil memasukkan utterance ke antrean
+type QueueResult struct {
+	ID       string `json:"id"`
+	State    string `json:"state"`
+	Position int    `json:"position,omitempty"` // 1 = berikutnya diputar
+	Owner    string `json:"owner,omitempty"`    // Client yang memegang audio
+}
+
+// QueuedUtterance utterance yang menunggu giliran
+type QueuedUtterance struct {
+	ID         string    `json:"id"`
+	ClientID   string    `json:"client_id,omitempty"`
+	Text       string    `json:"text"`
+	Priority   Priority  `json:"priority"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+
+	config TTSConfig
+}
+
+// SpeechQueue mengatur giliran bicara antar client (tab/frame) di atas satu TTSService.
+//
+// Aturan kepemilikan audio:
+//   - Client dari utterance yang sedang diputar adalah pemilik audio.
+//   - PriorityInterrupt dari client mana pun mengambil alih audio, karena itu
+//     berarti pengguna baru saja berinteraksi dengan tab tersebut. Antrean lama
+//     milik client yang sama ikut dibuang, antrean client lain tetap menunggu.
+//   - Stop/Pause/Resume dengan client ID hanya berlaku untuk pemilik audio;
+//     client lain hanya bisa membersihkan antreannya sendiri.
+//   - Client ID kosong (mis. popup extension) dianggap kontrol global.
+//
+// service.Speak bisa lama (teks dirender dulu), jadi dipanggil tanpa memegang
+// mu. starting menandai utterance yang sedang dimulai; jika setelah Speak
+// selesai starting sudah diganti atau dibatalkan, utterance itu kalah.
+type SpeechQueue struct {
+	service TTSService
+	speakMu sync.Mutex // Mengurutkan panggilan service.Speak
+
+	mu       sync.Mutex
+	current  *QueuedUtterance
+	starting *QueuedUtterance
+	pending  []*QueuedUtterance
+}
+
+// NewSpeechQueue membuat antrean di atas service dan menjalankan worker-nya
+func NewSpeechQueue(service TTSService) *SpeechQueue {
+	q := &SpeechQueue{service: service}
+	go q.run(100 * time.Millisecond)
+	return q
+}
+
+// Enqueue memasukkan utterance sesuai prioritasnya
+func (q *SpeechQueue) Enqueue(req TTSRequest) (*QueueResult, error) {
+	priority, err := ParsePriority(string(req.Priority))
+	if err != nil {
+		return nil, err
+	}
+
+	item := &QueuedUtterance{
+		ID:         newID(),
+		ClientID:   req.ClientID,
+		Text:       req.Text,
+		Priority:   priority,
+		EnqueuedAt: time.Now(),
+		config:     req.Config,
+	}
+
+	q.mu.Lock()
+	q.refreshLocked()
+	busy := q.current != nil || q.starting != nil || len(q.pending) > 0
+
+	switch priority {
+	case PriorityDrop:
+		if busy {
+			defer q.mu.Unlock()
+			return &QueueResult{ID: item.ID, State: QueueStateDropped, Owner: q.ownerLocked()}, nil
+		}
+	case PriorityQueue:
+		if busy {
+			defer q.mu.Unlock()
+			if len(q.pending) >= maxQueueLength {
+				return nil, ErrQueueFull
+			}
+			q.pending = append(q.pending, item)
+			return &QueueResult{
+				ID:       item.ID,
+				State:    QueueStateQueued,
+				Position: len(q.pending),
+				Owner:    q.ownerLocked(),
+			}, nil
+		}
+	case PriorityInterrupt:
+		// Hover baru menggantikan antrean lama dari tab yang sama
+		q.removePendingLocked(item.ClientID)
+		if q.current != nil {
+			q.service.Stop()
+			q.current = nil
+		}
+	}
+	q.starting = item
+	q.mu.Unlock()
+
+	started, err := q.start(item)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		// Utterance lain atau Stop datang selama Speak berjalan
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return &QueueResult{ID: item.ID, State: QueueStateDropped, Owner: q.ownerLocked()}, nil
+	}
+	return &QueueResult{ID: item.ID, State: QueueStatePlaying, Owner: item.ClientID}, nil
+}
+
+// StopClient menghentikan audio milik client dan membuang antreannya.
+// Client ID kosong menghentikan semuanya.
+func (q *SpeechQueue) StopClient(clientID string) error {
+	q.mu.Lock()
+	q.refreshLocked()
+	if clientID == "" {
+		q.pending = nil
+	} else {
+		q.removePendingLocked(clientID)
+	}
+	// Utterance yang sedang dimulai dihentikan setelah Speak-nya selesai
+	if q.starting != nil && (clientID == "" || q.starting.ClientID == clientID) {
+		q.starting = nil
+	}
+
+	if q.current == nil || (clientID != "" && q.current.ClientID != clientID) {
+		q.mu.Unlock()
+		return nil
+	}
+
+	err := q.service.Stop()
+	q.current = nil
+	q.mu.Unlock()
+
+	q.startNext()
+	return err
+}
+
+// PauseClient menjeda audio jika client adalah pemiliknya
+func (q *SpeechQueue) PauseClient(clientID string) error {
+	return q.controlOwned(clientID, q.service.Pause)
+}
+
+// ResumeClient melanjutkan audio jika client adalah pemiliknya
+func (q *SpeechQueue) ResumeClient(clientID string) error {
+	return q.controlOwned(clientID, q.service.Resume)
+}
+
+// NextClient melompat ke kalimat berikutnya jika client adalah pemiliknya
+func (q *SpeechQueue) NextClient(clientID string) error {
+	return q.navigate(clientID, Navigator.NextSentence)
+}
+
+// PreviousClient kembali ke kalimat sebelumnya jika client adalah pemiliknya
+func (q *SpeechQueue) PreviousClient(clientID string) error {
+	return q.navigate(clientID, Navigator.PreviousSentence)
+}
+
+// RepeatClient mengulang kalimat aktif jika client adalah pemiliknya
+func (q *SpeechQueue) RepeatClient(clientID string) error {
+	return q.navigate(clientID, Navigator.RepeatSentence)
+}
+
+func (q *SpeechQueue) navigate(clientID string, action func(Navigator) error) error {
+	navigator, ok := findNavigator(q.service)
+	if !ok {
+		return ErrNotSupported
+	}
+	return q.controlOwned(clientID, func() error {
+		return action(navigator)
+	})
+}
+
+func (q *SpeechQueue) controlOwned(clientID string, action func() error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.refreshLocked()
+	if q.current == nil {
+		return ErrNotSpeaking
+	}
+	if clientID != "" && q.current.ClientID != clientID {
+		return ErrNotOwner
+	}
+	return action()
+}
+
+// Pending mengembalikan salinan antrean yang menunggu
+func (q *SpeechQueue) Pending() []QueuedUtterance {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := make([]QueuedUtterance, 0, len(q.pending))
+	for _, item := range q.pending {
+		pending = append(pending, *item)
+	}
+	return pending
+}
+
+// Speak memutar teks dengan PriorityInterrupt (perilaku lama /api/tts)
+func (q *SpeechQueue) Speak(text string, config TTSConfig) (*TTSResponse, error) {
+	startTime := time.Now()
+	result, err := q.Enqueue(TTSRequest{Text: text, Config: config, Priority: PriorityInterrupt})
+	if err != nil {
+		return &TTSResponse{Success: false, Error: err.Error(), Timestamp: time.Now()}, nil
+	}
+	return &TTSResponse{
+		Success:   result.State == QueueStatePlaying,
+		Duration:  time.Since(startTime).Seconds() * 1000,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// Synthesize tidak memakai antrean karena audio tidak diputar di host
+func (q *SpeechQueue) Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error) {
+	return q.service.Synthesize(ctx, text, config)
+}
+
+// Stop menghentikan audio dan mengosongkan antrean
+func (q *SpeechQueue) Stop() error {
+	return q.StopClient("")
+}
+
+// Pause menjeda audio yang sedang diputar
+func (q *SpeechQueue) Pause() error {
+	return q.PauseClient("")
+}
+
+// Resume melanjutkan audio yang dijeda
+func (q *SpeechQueue) Resume() error {
+	return q.ResumeClient("")
+}
+
+// Status melaporkan utterance yang diputar beserta pemilik dan panjang antrean
+func (q *SpeechQueue) Status() TTSStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.refreshLocked()
+	status := q.service.Status()
+	if q.current != nil {
+		status.ClientID = q.current.ClientID
+	}
+	status.QueueLength = len(q.pending)
+	return status
+}
+
+// GetVoices meneruskan ke service
+func (q *SpeechQueue) GetVoices() ([]string, error) {
+	return q.service.GetVoices()
+}
+
+// IsSpeaking mengecek apakah ada utterance yang sedang diputar
+func (q *SpeechQueue) IsSpeaking() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.refreshLocked()
+	return q.current != nil
+}
+
+// run memantau service dan memutar antrean berikutnya saat utterance selesai
+func (q *SpeechQueue) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		q.startNext()
+	}
+}
+
+// refreshLocked melepas current jika service sudah selesai bicara
+func (q *SpeechQueue) refreshLocked() {
+	if q.current != nil && !q.service.IsSpeaking() {
+		q.current = nil
+	}
+}
+
+// startNext memutar utterance berikutnya jika audio sedang kosong
+func (q *SpeechQueue) startNext() {
+	for {
+		q.mu.Lock()
+		q.refreshLocked()
+		if q.current != nil || q.starting != nil || len(q.pending) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		next := q.pending[0]
+		q.pending = q.pending[1:]
+		q.starting = next
+		q.mu.Unlock()
+
+		if _, err := q.start(next); err != nil {
+			log.Printf("TTS queue: failed to start %s: %v", next.ID, err)
+		}
+	}
+}
+
+// start memanggil service.Speak untuk item yang sudah dipasang di starting
+// (tanpa memegang mu), lalu menjadikannya current jika belum digantikan.
+// started false berarti item digantikan atau dihentikan sebelum atau
+// selama Speak berjalan.
+func (q *SpeechQueue) start(item *QueuedUtterance) (started bool, err error) {
+	q.speakMu.Lock()
+	// Item yang digantikan saat menunggu speakMu tidak boleh bicara,
+	// karena Speak-nya akan memotong audio penggantinya
+	q.mu.Lock()
+	superseded := q.starting != item
+	q.mu.Unlock()
+	if superseded {
+		q.speakMu.Unlock()
+		return false, nil
+	}
+	response, err := q.service.Speak(item.Text, item.config)
+	q.speakMu.Unlock()
+	if err == nil && !response.Success {
+		err = errors.New(response.Error)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.starting != item {
+		// Tidak ada yang menggantikan (Stop), jadi audio item ini dihentikan;
+		// jika ada pengganti, Speak berikutnya sudah memotongnya
+		if err == nil && q.starting == nil && q.current == nil {
+			q.service.Stop()
+		}
+		return false, err
+	}
+	q.starting = nil
+	if err != nil {
+		return false, err
+	}
+	q.current = item
+	return true, nil
+}
+
+func (q *SpeechQueue) removePendingLocked(clientID string) {
+	kept := q.pending[:0]
+	for _, item := range q.pending {
+		if item.ClientID != clientID {
+			kept = append(kept, item)
+		}
+	}
+	q.pending = kept
+}
+
+func (q *SpeechQueue) ownerLocked() string {
+	switch {
+	case q.current != nil:
+		return q.current.ClientID
+	case q.starting != nil:
+		return q.starting.ClientID
+	}
+	return ""
+}
+
+// newID membuat ID acak pendek untuk utterance/job
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
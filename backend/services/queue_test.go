@@ -0,0 +1,314 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowTTSService service palsu yang Speak-nya bisa ditahan sampai test
+// melepasnya, seperti teks panjang yang masih dirender
+type slowTTSService struct {
+	TTSService
+
+	mu       sync.Mutex
+	speaking string
+	spoken   []string
+	stops    int
+	gates    map[string]chan struct{}
+	entered  chan string
+}
+
+func newSlowTTSService() *slowTTSService {
+	return &slowTTSService{gates: map[string]chan struct{}{}, entered: make(chan string, 10)}
+}
+
+// hold membuat Speak untuk text menunggu sampai channel yang dikembalikan ditutup
+func (s *slowTTSService) hold(text string) chan struct{} {
+	gate := make(chan struct{})
+	s.mu.Lock()
+	s.gates[text] = gate
+	s.mu.Unlock()
+	return gate
+}
+
+func (s *slowTTSService) Speak(text string, config TTSConfig) (*TTSResponse, error) {
+	s.mu.Lock()
+	gate := s.gates[text]
+	s.mu.Unlock()
+	s.entered <- text
+	if gate != nil {
+		<-gate
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.speaking = text
+	s.spoken = append(s.spoken, text)
+	return &TTSResponse{Success: true}, nil
+}
+
+func (s *slowTTSService) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.speaking = ""
+	s.stops++
+	return nil
+}
+
+func (s *slowTTSService) IsSpeaking() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.speaking != ""
+}
+
+func (s *slowTTSService) Status() TTSStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return TTSStatus{Speaking: s.speaking != "", Text: s.speaking}
+}
+
+func (s *slowTTSService) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.speaking
+}
+
+// enqueueAsync menjalankan Enqueue di goroutine dan menunggu Speak-nya dimulai
+func enqueueAsync(t *testing.T, q *SpeechQueue, service *slowTTSService, req TTSRequest) chan *QueueResult {
+	t.Helper()
+	done := make(chan *QueueResult, 1)
+	go func() {
+		result, err := q.Enqueue(req)
+		if err != nil {
+			t.Errorf("Enqueue(%q): %v", req.Text, err)
+		}
+		done <- result
+	}()
+	select {
+	case text := <-service.entered:
+		if text != req.Text {
+			t.Fatalf("Speak(%q) started, want %q", text, req.Text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Speak(%q) did not start", req.Text)
+	}
+	return done
+}
+
+func waitResult(t *testing.T, done chan *QueueResult) *QueueResult {
+	t.Helper()
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(2 * time.Second):
+		t.Fatal("Enqueue did not return")
+		return nil
+	}
+}
+
+func TestSpeechQueueDoesNotHoldLockDuringSpeak(t *testing.T) {
+	service := newSlowTTSService()
+	q := &SpeechQueue{service: service}
+	gate := service.hold("panjang")
+	done := enqueueAsync(t, q, service, TTSRequest{Text: "panjang", ClientID: "tab-1"})
+
+	// Status, antrean dan drop-if-busy tetap menjawab selama Speak berjalan
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		q.Status()
+		q.IsSpeaking()
+		result, err := q.Enqueue(TTSRequest{Text: "lain", ClientID: "tab-2", Priority: PriorityDrop})
+		if err != nil || result.State != QueueStateDropped || result.Owner != "tab-1" {
+			t.Errorf("drop while starting = %+v, %v; want dropped with owner tab-1", result, err)
+		}
+		result, err = q.Enqueue(TTSRequest{Text: "antre", ClientID: "tab-2", Priority: PriorityQueue})
+		if err != nil || result.State != QueueStateQueued || result.Position != 1 {
+			t.Errorf("queue while starting = %+v, %v; want queued at 1", result, err)
+		}
+	}()
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("queue blocked while Speak was running")
+	}
+
+	close(gate)
+	if result := waitResult(t, done); result.State != QueueStatePlaying || result.Owner != "tab-1" {
+		t.Errorf("result = %+v, want playing by tab-1", result)
+	}
+	if status := q.Status(); status.ClientID != "tab-1" || status.QueueLength != 1 {
+		t.Errorf("status = %+v, want tab-1 playing with one queued", status)
+	}
+}
+
+func TestSpeechQueueStopDuringSpeak(t *testing.T) {
+	service := newSlowTTSService()
+	q := &SpeechQueue{service: service}
+	gate := service.hold("panjang")
+	done := enqueueAsync(t, q, service, TTSRequest{Text: "panjang", ClientID: "tab-1"})
+
+	if err := q.StopClient("tab-1"); err != nil {
+		t.Fatal(err)
+	}
+	close(gate)
+
+	if result := waitResult(t, done); result.State != QueueStateDropped {
+		t.Errorf("result = %+v, want dropped after Stop", result)
+	}
+	if text := service.current(); text != "" {
+		t.Errorf("service still speaking %q after Stop", text)
+	}
+	if q.IsSpeaking() {
+		t.Error("queue still speaking after Stop")
+	}
+}
+
+func TestSpeechQueueStopOtherClientDuringSpeak(t *testing.T) {
+	service := newSlowTTSService()
+	q := &SpeechQueue{service: service}
+	gate := service.hold("panjang")
+	done := enqueueAsync(t, q, service, TTSRequest{Text: "panjang", ClientID: "tab-1"})
+
+	// Client lain tidak bisa menghentikan audio yang bukan miliknya
+	if err := q.StopClient("tab-2"); err != nil {
+		t.Fatal(err)
+	}
+	close(gate)
+
+	if result := waitResult(t, done); result.State != QueueStatePlaying {
+		t.Errorf("result = %+v, want playing", result)
+	}
+	if text := service.current(); text != "panjang" {
+		t.Errorf("service speaking %q, want panjang", text)
+	}
+}
+
+func TestSpeechQueueInterruptDuringSpeak(t *testing.T) {
+	service := newSlowTTSService()
+	q := &SpeechQueue{service: service}
+	gate := service.hold("pertama")
+	first := enqueueAsync(t, q, service, TTSRequest{Text: "pertama", ClientID: "tab-1"})
+
+	// Interrupt kedua menunggu Speak pertama, lalu menggantikannya
+	second := make(chan *QueueResult, 1)
+	go func() {
+		result, err := q.Enqueue(TTSRequest{Text: "kedua", ClientID: "tab-2"})
+		if err != nil {
+			t.Errorf("Enqueue(kedua): %v", err)
+		}
+		second <- result
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		q.mu.Lock()
+		replaced := q.starting != nil && q.starting.Text == "kedua"
+		q.mu.Unlock()
+		if replaced {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("second interrupt did not take over")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(gate)
+
+	if result := waitResult(t, first); result.State != QueueStateDropped || result.Owner != "tab-2" {
+		t.Errorf("first = %+v, want dropped in favour of tab-2", result)
+	}
+	<-service.entered
+	if result := waitResult(t, second); result.State != QueueStatePlaying {
+		t.Errorf("second = %+v, want playing", result)
+	}
+	if text := service.current(); text != "kedua" {
+		t.Errorf("service speaking %q, want kedua", text)
+	}
+	if status := q.Status(); status.ClientID != "tab-2" {
+		t.Errorf("owner = %q, want tab-2", status.ClientID)
+	}
+}
+
+// waitStarting menunggu sampai utterance dengan teks text dipasang di starting
+func waitStarting(t *testing.T, q *SpeechQueue, text string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		q.mu.Lock()
+		replaced := q.starting != nil && q.starting.Text == text
+		q.mu.Unlock()
+		if replaced {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s did not take over", text)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSpeechQueueSupersededWhileWaitingDoesNotSpeak(t *testing.T) {
+	service := newSlowTTSService()
+	q := &SpeechQueue{service: service}
+	gate := service.hold("lama")
+	old := enqueueAsync(t, q, service, TTSRequest{Text: "lama", ClientID: "tab-1"})
+
+	// A menunggu speakMu selama Speak "lama" berjalan, lalu digantikan B
+	enqueue := func(text string) chan *QueueResult {
+		done := make(chan *QueueResult, 1)
+		go func() {
+			result, err := q.Enqueue(TTSRequest{Text: text, ClientID: "tab-2"})
+			if err != nil {
+				t.Errorf("Enqueue(%s): %v", text, err)
+			}
+			done <- result
+		}()
+		return done
+	}
+	a := enqueue("A")
+	waitStarting(t, q, "A")
+	b := enqueue("B")
+	waitStarting(t, q, "B")
+	close(gate)
+
+	if result := waitResult(t, a); result.State != QueueStateDropped {
+		t.Errorf("A = %+v, want dropped", result)
+	}
+	if result := waitResult(t, b); result.State != QueueStatePlaying {
+		t.Errorf("B = %+v, want playing", result)
+	}
+	waitResult(t, old)
+	service.mu.Lock()
+	spoken := service.spoken
+	service.mu.Unlock()
+	if len(spoken) != 2 || spoken[1] != "B" {
+		t.Errorf("spoken = %v, want [lama B] without A", spoken)
+	}
+	if text := service.current(); text != "B" {
+		t.Errorf("service speaking %q, want B", text)
+	}
+}
+
+func TestSpeechQueuePlaysNextWhenDone(t *testing.T) {
+	service := newSlowTTSService()
+	q := &SpeechQueue{service: service}
+
+	if result, err := q.Enqueue(TTSRequest{Text: "satu", ClientID: "tab-1"}); err != nil || result.State != QueueStatePlaying {
+		t.Fatalf("Enqueue(satu) = %+v, %v", result, err)
+	}
+	<-service.entered
+	if result, err := q.Enqueue(TTSRequest{Text: "dua", ClientID: "tab-2", Priority: PriorityQueue}); err != nil || result.State != QueueStateQueued {
+		t.Fatalf("Enqueue(dua) = %+v, %v", result, err)
+	}
+
+	service.Stop() // Utterance pertama selesai diputar
+	q.startNext()
+	<-service.entered
+	if text := service.current(); text != "dua" {
+		t.Errorf("service speaking %q, want dua", text)
+	}
+	if status := q.Status(); status.ClientID != "tab-2" || status.QueueLength != 0 {
+		t.Errorf("status = %+v, want tab-2 playing with an empty queue", status)
+	}
+}
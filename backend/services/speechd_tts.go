@@ -0,0 +1,525 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// SpeechDispatcherSocket lokasi socket speech-dispatcher milik user: dari
+// SPEECHD_ADDRESS (unix_socket:/path), atau lokasi default di XDG_RUNTIME_DIR
+func SpeechDispatcherSocket() string {
+	if address := os.Getenv("SPEECHD_ADDRESS"); strings.HasPrefix(address, "unix_socket:") {
+		return strings.TrimPrefix(address, "unix_socket:")
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return filepath.Join(runtimeDir, "speech-dispatcher", "speechd.sock")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cache", "speech-dispatcher", "speechd.sock")
+}
+
+// SpeechDispatcherService memutar suara lewat speech-dispatcher (SSIP), sehingga
+// voice dan output module pilihan user di desktop Linux ikut dipakai.
+//
+// Teks dikirim sebagai SSML dengan index mark sebelum setiap kalimat dan kata;
+// event INDEX MARK dari speech-dispatcher dipakai untuk progress dan kata aktif.
+// speech-dispatcher tidak bisa merender ke file, jadi Synthesize diteruskan ke
+// render (mis. SystemTTSService).
+type SpeechDispatcherService struct {
+	socket string
+	render TTSService
+
+	ctlMu sync.Mutex // Mengurutkan Speak, Stop dan navigasi
+	mu    sync.Mutex // Melindungi state di bawah; tidak dipegang selama I/O
+
+	client    *ssipClient
+	sequence  int    // Naik setiap teks dikirim ulang, menjadi awalan nama mark
+	msgID     string // Pesan SSIP yang sedang diucapkan
+	current   *TTSStatus
+	config    TTSConfig // Config utterance yang sedang diputar (bahasa dan leksikon)
+	segments  []Segment
+	words     map[int]Segment // Kata berdasarkan offset awal
+	pausedAt  time.Time
+	pausedFor time.Duration
+}
+
+// NewSpeechDispatcherService membuat service untuk socket; koneksi dibuka saat
+// pertama dipakai dan dibuka ulang jika speech-dispatcher restart
+func NewSpeechDispatcherService(socket string, render TTSService) *SpeechDispatcherService {
+	return &SpeechDispatcherService{socket: socket, render: render}
+}
+
+// Speak mengirim teks ke speech-dispatcher, menggantikan teks sebelumnya
+func (s *SpeechDispatcherService) Speak(text string, config TTSConfig) (*TTSResponse, error) {
+	s.ctlMu.Lock()
+	defer s.ctlMu.Unlock()
+
+	if strings.TrimSpace(text) == "" {
+		return &TTSResponse{
+			Success:   false,
+			Error:     "Text is empty",
+			Timestamp: time.Now(),
+		}, nil
+	}
+	if utf8.RuneCountInString(text) > MaxTextRunes {
+		return &TTSResponse{
+			Success:   false,
+			Error:     fmt.Sprintf("Text too long (max %d characters)", MaxTextRunes),
+			Timestamp: time.Now(),
+		}, nil
+	}
+
+	config = applyConfigDefaults(config)
+	startTime := time.Now()
+
+	fail := func(err error) (*TTSResponse, error) {
+		s.mu.Lock()
+		s.resetLocked()
+		s.mu.Unlock()
+		return &TTSResponse{
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		}, nil
+	}
+
+	client, err := s.connect()
+	if err != nil {
+		return fail(err)
+	}
+	if _, err := client.Command("CANCEL self"); err != nil {
+		return fail(err)
+	}
+
+	segments := limitSegments(SplitSentences(text), speakSegmentRunes)
+	words := map[int]Segment{}
+	for _, segment := range segments {
+		for _, word := range splitWords(segment) {
+			words[word.Start] = word
+		}
+	}
+
+	s.mu.Lock()
+	s.config = config
+	s.segments = segments
+	s.words = words
+	s.pausedFor = 0
+	s.current = &TTSStatus{
+		Speaking:            true,
+		Text:                text,
+		Language:            config.Language,
+		StartedAt:           &startTime,
+		EstimatedDurationMs: estimateSpeechMs(text, config.Speed),
+		SentenceCount:       len(segments),
+	}
+	s.mu.Unlock()
+
+	if err := s.applyConfig(client, config); err != nil {
+		return fail(err)
+	}
+	if err := s.speakFrom(client, 0); err != nil {
+		return fail(err)
+	}
+
+	return &TTSResponse{
+		Success:   true,
+		Duration:  time.Since(startTime).Seconds() * 1000,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// Synthesize diteruskan ke render karena speech-dispatcher hanya memutar suara
+func (s *SpeechDispatcherService) Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error) {
+	if s.render == nil {
+		return nil, ErrNotSupported
+	}
+	return s.render.Synthesize(ctx, text, config)
+}
+
+// Stop membatalkan semua pesan milik koneksi ini
+func (s *SpeechDispatcherService) Stop() error {
+	s.ctlMu.Lock()
+	defer s.ctlMu.Unlock()
+
+	s.mu.Lock()
+	client := s.client
+	s.resetLocked()
+	s.mu.Unlock()
+
+	if client == nil || client.Closed() {
+		return nil
+	}
+	_, err := client.Command("CANCEL self")
+	return err
+}
+
+// Pause menjeda speech (speech-dispatcher berhenti di index mark berikutnya)
+func (s *SpeechDispatcherService) Pause() error {
+	return s.setPaused(true)
+}
+
+// Resume melanjutkan speech yang sedang dijeda
+func (s *SpeechDispatcherService) Resume() error {
+	return s.setPaused(false)
+}
+
+func (s *SpeechDispatcherService) setPaused(paused bool) error {
+	s.ctlMu.Lock()
+	defer s.ctlMu.Unlock()
+
+	s.mu.Lock()
+	client := s.client
+	if s.current == nil || client == nil {
+		s.mu.Unlock()
+		return ErrNotSpeaking
+	}
+	if s.current.Paused == paused {
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+
+	command := "RESUME self"
+	if paused {
+		command = "PAUSE self"
+	}
+	if _, err := client.Command(command); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != nil {
+		s.setPausedLocked(paused)
+	}
+	return nil
+}
+
+// NextSentence melompat ke kalimat berikutnya
+func (s *SpeechDispatcherService) NextSentence() error {
+	return s.jumpSentence(1)
+}
+
+// PreviousSentence kembali ke kalimat sebelumnya (atau mengulang kalimat pertama)
+func (s *SpeechDispatcherService) PreviousSentence() error {
+	return s.jumpSentence(-1)
+}
+
+// RepeatSentence mengulang kalimat yang sedang diucapkan dari awal
+func (s *SpeechDispatcherService) RepeatSentence() error {
+	return s.jumpSentence(0)
+}
+
+// jumpSentence membatalkan pesan aktif lalu mengirim ulang teks mulai dari kalimat tujuan
+func (s *SpeechDispatcherService) jumpSentence(delta int) error {
+	s.ctlMu.Lock()
+	defer s.ctlMu.Unlock()
+
+	s.mu.Lock()
+	client := s.client
+	if s.current == nil || client == nil {
+		s.mu.Unlock()
+		return ErrNotSpeaking
+	}
+	index := s.current.SentenceIndex + delta
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(s.segments) {
+		s.mu.Unlock()
+		return ErrNoSentence
+	}
+	// Event CANCELED untuk pesan lama tidak boleh menghapus state
+	s.msgID = ""
+	s.mu.Unlock()
+
+	if _, err := client.Command("CANCEL self"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.current == nil {
+		s.mu.Unlock()
+		return ErrNotSpeaking
+	}
+	// Navigasi juga melanjutkan speech yang sedang dijeda
+	s.setPausedLocked(false)
+	s.setSentenceLocked(index)
+	s.mu.Unlock()
+
+	if err := s.speakFrom(client, index); err != nil {
+		s.mu.Lock()
+		s.resetLocked()
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Status melaporkan utterance yang sedang diucapkan, dengan posisi dari index mark
+func (s *SpeechDispatcherService) Status() TTSStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current == nil {
+		return TTSStatus{}
+	}
+
+	status := *s.current
+	elapsed := time.Since(*status.StartedAt) - s.pausedFor
+	if status.Paused {
+		elapsed -= time.Since(s.pausedAt)
+	}
+	status.ElapsedMs = elapsed.Seconds() * 1000
+
+	position := status.SentenceStart
+	if status.Word != nil {
+		word := *status.Word
+		status.Word = &word
+		position = word.Start
+	}
+	if total := utf8.RuneCountInString(status.Text); total > 0 {
+		status.Progress = float64(position) / float64(total)
+	}
+	return status
+}
+
+// GetVoices membaca voice dari output module yang aktif (LIST SYNTHESIS_VOICES)
+func (s *SpeechDispatcherService) GetVoices() ([]string, error) {
+	s.ctlMu.Lock()
+	client, err := s.connect()
+	s.ctlMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := client.Command("LIST SYNTHESIS_VOICES")
+	if err != nil {
+		return nil, err
+	}
+
+	// Setiap baris: nama, bahasa dan varian, dipisah tab
+	voices := []string{}
+	for _, line := range reply.Lines {
+		name := strings.SplitN(line, "\t", 2)[0]
+		if !strings.Contains(line, "\t") {
+			name = strings.SplitN(line, " ", 2)[0]
+		}
+		if name != "" {
+			voices = append(voices, name)
+		}
+	}
+	return voices, nil
+}
+
+// IsSpeaking mengecek apakah sedang ada speech yang berjalan
+func (s *SpeechDispatcherService) IsSpeaking() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current != nil
+}
+
+// connect membuka koneksi jika belum ada atau sudah terputus (ctlMu dipegang)
+func (s *SpeechDispatcherService) connect() (*ssipClient, error) {
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+	if client != nil && !client.Closed() {
+		return client, nil
+	}
+
+	client, err := dialSSIP(s.socket, s.handleEvent)
+	if err != nil {
+		return nil, fmt.Errorf("speech-dispatcher not reachable at %s: %v", s.socket, err)
+	}
+	for _, command := range []string{
+		"SET self CLIENT_NAME user:lansia:tts",
+		"SET self NOTIFICATION all on",
+		"SET self SSML_MODE on",
+	} {
+		if _, err := client.Command(command); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	s.client = client
+	s.mu.Unlock()
+	return client, nil
+}
+
+// applyConfig mengatur rate, volume, bahasa dan voice untuk pesan berikutnya
+func (s *SpeechDispatcherService) applyConfig(client *ssipClient, config TTSConfig) error {
+	commands := []string{
+		fmt.Sprintf("SET self RATE %d", ssipRate(config.Speed)),
+		fmt.Sprintf("SET self VOLUME %d", ssipVolume(config.Volume)),
+		"SET self LANGUAGE " + strings.ToLower(strings.SplitN(config.Language, "-", 2)[0]),
+	}
+	if config.Voice != "" {
+		commands = append(commands, "SET self SYNTHESIS_VOICE "+strings.Fields(config.Voice)[0])
+	}
+	for _, command := range commands {
+		if _, err := client.Command(command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// speakFrom mengirim teks mulai dari kalimat ke-index sebagai satu pesan SSML
+func (s *SpeechDispatcherService) speakFrom(client *ssipClient, index int) error {
+	s.mu.Lock()
+	s.sequence++
+	sequence := s.sequence
+	s.msgID = ""
+	ssml := ssipSSML(sequence, s.segments, index, s.config)
+	s.mu.Unlock()
+
+	return client.Speak(ssml, func(msgID string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.sequence == sequence && s.current != nil {
+			s.msgID = msgID
+		}
+	})
+}
+
+// handleEvent memproses event SSIP untuk pesan yang sedang diucapkan
+func (s *SpeechDispatcherService) handleEvent(event ssipMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current == nil || len(event.Lines) == 0 || event.Lines[0] != s.msgID {
+		return
+	}
+
+	switch event.Code {
+	case ssipEventIndexMark:
+		if len(event.Lines) >= 3 {
+			s.applyMarkLocked(event.Lines[2])
+		}
+	case ssipEventEnd, ssipEventCanceled:
+		s.resetLocked()
+	case ssipEventPaused:
+		s.setPausedLocked(true)
+	case ssipEventResumed:
+		s.setPausedLocked(false)
+	}
+}
+
+// applyMarkLocked memperbarui posisi dari nama mark "<sequence>s<kalimat>"
+// atau "<sequence>w<offset kata>"
+func (s *SpeechDispatcherService) applyMarkLocked(name string) {
+	split := strings.IndexAny(name, "sw")
+	if split <= 0 || name[:split] != strconv.Itoa(s.sequence) {
+		return
+	}
+	value, err := strconv.Atoi(name[split+1:])
+	if err != nil {
+		return
+	}
+
+	switch name[split] {
+	case 's':
+		if value < len(s.segments) {
+			s.setSentenceLocked(value)
+		}
+	case 'w':
+		if word, ok := s.words[value]; ok {
+			s.current.Word = &Mark{Type: MarkWord, Text: word.Text, Start: word.Start, End: word.End}
+		}
+	}
+}
+
+func (s *SpeechDispatcherService) setSentenceLocked(index int) {
+	segment := s.segments[index]
+	s.current.SentenceIndex = index
+	s.current.SentenceText = segment.Text
+	s.current.SentenceStart = segment.Start
+	s.current.SentenceEnd = segment.End
+	s.current.Word = nil
+}
+
+func (s *SpeechDispatcherService) setPausedLocked(paused bool) {
+	if s.current.Paused == paused {
+		return
+	}
+	s.current.Paused = paused
+	if paused {
+		s.pausedAt = time.Now()
+	} else {
+		s.pausedFor += time.Since(s.pausedAt)
+	}
+}
+
+// resetLocked menghapus state utterance aktif
+func (s *SpeechDispatcherService) resetLocked() {
+	s.current = nil
+	s.segments = nil
+	s.words = nil
+	s.msgID = ""
+}
+
+// ssipSSML menyusun SSML satu baris dengan mark sebelum setiap kalimat dan kata
+func ssipSSML(sequence int, segments []Segment, from int, config TTSConfig) string {
+	escaper := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;")
+
+	var ssml strings.Builder
+	ssml.WriteString("<speak>")
+	for i := from; i < len(segments); i++ {
+		fmt.Fprintf(&ssml, `<mark name="%ds%d"/>`, sequence, i)
+
+		// Normalisasi per kalimat; mark tetap di awal setiap kata asli, dan
+		// bacaan yang mencakup beberapa kata (Rp 1.250.000) ikut kata pertamanya
+		sentence := segments[i].Text
+		spans := normalizeSpans(sentence, config)
+		offsets := runeByteOffsets(sentence)
+		for _, word := range splitWords(segments[i]) {
+			start, end := offsets[word.Start-segments[i].Start], offsets[word.End-segments[i].Start]
+			spoken := cleanText(spokenRange(sentence, spans, start, end))
+			fmt.Fprintf(&ssml, `<mark name="%dw%d"/>%s `, sequence, word.Start, escaper.Replace(spoken))
+		}
+	}
+	ssml.WriteString("</speak>")
+	return ssml.String()
+}
+
+// runeByteOffsets offset byte untuk setiap offset rune di text (termasuk akhir teks)
+func runeByteOffsets(text string) []int {
+	offsets := make([]int, 0, len(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	return append(offsets, len(text))
+}
+
+// ssipRate mengubah speed 0.5 - 2.0 menjadi rate SSIP -100 - 100 (0 = normal)
+func ssipRate(speed float64) int {
+	if speed >= 1 {
+		return clampInt(int((speed-1)*100), 0, 100)
+	}
+	return clampInt(int((speed-1)*200), -100, 0)
+}
+
+// ssipVolume mengubah volume 0.0 - 1.0 menjadi volume SSIP -100 - 100
+func ssipVolume(volume float64) int {
+	return clampInt(int(volume*200-100), -100, 100)
+}
+
+func clampInt(value, low, high int) int {
+	if value < low {
+		return low
+	}
+	if value > high {
+		return high
+	}
+	return value
+}
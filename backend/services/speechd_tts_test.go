@@ -0,0 +1,394 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSSIP server speech-dispatcher palsu di Unix socket. Perintah dicatat,
+// data SPEAK disimpan, dan test mengirim event index mark, END atau PAUSED
+// untuk pesan terakhir seperti yang dilakukan speech-dispatcher.
+type fakeSSIP struct {
+	t      *testing.T
+	socket string
+
+	mu       sync.Mutex
+	conn     net.Conn
+	commands []string
+	messages []string // Data SSML setiap SPEAK
+	nextID   int
+	spoken   chan struct{}
+}
+
+func newFakeSSIP(t *testing.T) *fakeSSIP {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "speechd.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets not available: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSSIP{t: t, socket: socket, spoken: make(chan struct{}, 16)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+			server.mu.Lock()
+			server.conn = conn
+			server.mu.Unlock()
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (f *fakeSSIP) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	var data []string
+	receiving := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if receiving {
+			if line != "." {
+				data = append(data, strings.TrimPrefix(line, "."))
+				continue
+			}
+			receiving = false
+			f.mu.Lock()
+			f.nextID++
+			id := f.nextID
+			f.messages = append(f.messages, strings.Join(data, "\n"))
+			f.mu.Unlock()
+			f.send(fmt.Sprintf("225-%d", id), "225 OK MESSAGE QUEUED")
+			f.spoken <- struct{}{}
+			continue
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, line)
+		f.mu.Unlock()
+
+		switch {
+		case line == "SPEAK":
+			receiving = true
+			data = nil
+			f.send("230 OK RECEIVING DATA")
+		case line == "LIST SYNTHESIS_VOICES":
+			f.send("249-ibu-ani\tid\tnone", "249-cmu-slt\ten\tfemale1", "249 OK VOICE LIST SENT")
+		case line == "CANCEL self":
+			f.send("210 OK CANCELED")
+		case line == "PAUSE self":
+			f.send("210 OK PAUSED")
+		case line == "RESUME self":
+			f.send("210 OK RESUMED")
+		case strings.HasPrefix(line, "SET self "):
+			f.send("203 OK SET")
+		default:
+			f.send("300 ERR UNKNOWN COMMAND")
+		}
+	}
+}
+
+// send menulis baris-baris jawaban atau event sekaligus
+func (f *fakeSSIP) send(lines ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.conn.Write([]byte(strings.Join(lines, "\r\n") + "\r\n")); err != nil {
+		f.t.Errorf("fake SSIP write: %v", err)
+	}
+}
+
+// event mengirim event untuk pesan terakhir, mis. event(700, "INDEX MARK", "1s0")
+func (f *fakeSSIP) event(code int, text string, mark ...string) {
+	f.mu.Lock()
+	id := f.nextID
+	f.mu.Unlock()
+	f.eventFor(id, code, text, mark...)
+}
+
+// eventFor mengirim event untuk pesan dengan id tertentu
+func (f *fakeSSIP) eventFor(id, code int, text string, mark ...string) {
+	lines := []string{fmt.Sprintf("%d-%d", code, id), fmt.Sprintf("%d-1", code)}
+	for _, name := range mark {
+		lines = append(lines, fmt.Sprintf("%d-%s", code, name))
+	}
+	f.send(append(lines, fmt.Sprintf("%d %s", code, text))...)
+}
+
+// waitSpeak menunggu SPEAK berikutnya dan mengembalikan SSML-nya
+func (f *fakeSSIP) waitSpeak() string {
+	f.t.Helper()
+	select {
+	case <-f.spoken:
+	case <-time.After(2 * time.Second):
+		f.t.Fatal("no SPEAK received")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.messages[len(f.messages)-1]
+}
+
+func (f *fakeSSIP) hasCommand(command string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.commands {
+		if c == command {
+			return true
+		}
+	}
+	return false
+}
+
+var ssipMarkPattern = regexp.MustCompile(`<mark name="(\d+)s(\d+)"/>`)
+
+// firstSentenceMark nama mark kalimat pertama di SSML, mis. "2s1"
+func firstSentenceMark(t *testing.T, ssml string) (string, int) {
+	t.Helper()
+	match := ssipMarkPattern.FindStringSubmatch(ssml)
+	if match == nil {
+		t.Fatalf("no sentence mark in %q", ssml)
+	}
+	var sequence int
+	fmt.Sscan(match[1], &sequence)
+	return match[0][len(`<mark name="`) : len(match[0])-len(`"/>`)], sequence
+}
+
+// waitStatus menunggu sampai Status memenuhi cond (event diproses di goroutine pembaca)
+func waitStatus(t *testing.T, service *SpeechDispatcherService, what string, cond func(TTSStatus) bool) TTSStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		status := service.Status()
+		if cond(status) {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s, status = %+v", what, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+const speechdTestText = "Selamat pagi semua. Obat diminum dua kali sehari. Jangan lupa makan."
+
+func startSpeechd(t *testing.T) (*fakeSSIP, *SpeechDispatcherService, []Segment, string) {
+	t.Helper()
+	server := newFakeSSIP(t)
+	service := NewSpeechDispatcherService(server.socket, nil)
+
+	response, err := service.Speak(speechdTestText, TTSConfig{Language: "id-ID", Speed: 1.5, Volume: 0.25})
+	if err != nil || !response.Success {
+		t.Fatalf("Speak() = %+v, %v", response, err)
+	}
+	ssml := server.waitSpeak()
+	return server, service, SplitSentences(speechdTestText), ssml
+}
+
+func TestSpeechDispatcherAppliesConfig(t *testing.T) {
+	server, _, _, ssml := startSpeechd(t)
+
+	for _, command := range []string{
+		"SET self NOTIFICATION all on",
+		"SET self SSML_MODE on",
+		"CANCEL self",
+		"SET self RATE 50",
+		"SET self VOLUME -50",
+		"SET self LANGUAGE id",
+		"SPEAK",
+	} {
+		if !server.hasCommand(command) {
+			t.Errorf("command %q not sent", command)
+		}
+	}
+	if !strings.HasPrefix(ssml, "<speak>") || !strings.Contains(ssml, "Obat ") {
+		t.Errorf("SSML = %q", ssml)
+	}
+	if got := len(ssipMarkPattern.FindAllString(ssml, -1)); got != 3 {
+		t.Errorf("sentence marks = %d, want 3", got)
+	}
+}
+
+func TestSpeechDispatcherFollowsIndexMarks(t *testing.T) {
+	server, service, segments, ssml := startSpeechd(t)
+	_, sequence := firstSentenceMark(t, ssml)
+
+	status := service.Status()
+	if !status.Speaking || status.SentenceCount != 3 || status.SentenceIndex != 0 || status.Progress != 0 {
+		t.Fatalf("initial status = %+v", status)
+	}
+
+	// Mark kalimat kedua lalu kata "diminum" di kalimat itu
+	server.event(ssipEventIndexMark, "INDEX MARK", fmt.Sprintf("%ds1", sequence))
+	status = waitStatus(t, service, "sentence 1", func(s TTSStatus) bool { return s.SentenceIndex == 1 })
+	if status.SentenceText != segments[1].Text || status.SentenceStart != segments[1].Start {
+		t.Errorf("sentence = %q at %d, want %q at %d", status.SentenceText, status.SentenceStart, segments[1].Text, segments[1].Start)
+	}
+	if want := float64(segments[1].Start) / float64(len(speechdTestText)); status.Progress != want {
+		t.Errorf("progress = %v, want %v", status.Progress, want)
+	}
+
+	word := splitWords(segments[1])[1]
+	server.event(ssipEventIndexMark, "INDEX MARK", fmt.Sprintf("%dw%d", sequence, word.Start))
+	status = waitStatus(t, service, "word mark", func(s TTSStatus) bool { return s.Word != nil })
+	if status.Word.Text != "diminum" || status.Word.Start != word.Start {
+		t.Errorf("word = %+v, want diminum at %d", status.Word, word.Start)
+	}
+	if want := float64(word.Start) / float64(len(speechdTestText)); status.Progress != want {
+		t.Errorf("progress = %v, want %v", status.Progress, want)
+	}
+
+	// Mark dengan urutan lama atau kalimat yang tidak ada diabaikan
+	server.event(ssipEventIndexMark, "INDEX MARK", fmt.Sprintf("%ds2", sequence+1))
+	server.event(ssipEventIndexMark, "INDEX MARK", fmt.Sprintf("%ds9", sequence))
+	server.event(ssipEventIndexMark, "INDEX MARK", fmt.Sprintf("%ds2", sequence))
+	status = waitStatus(t, service, "sentence 2", func(s TTSStatus) bool { return s.SentenceIndex == 2 })
+	if status.Word != nil {
+		t.Errorf("word = %+v, want it cleared on a new sentence", status.Word)
+	}
+
+	server.event(ssipEventEnd, "END")
+	waitStatus(t, service, "end", func(s TTSStatus) bool { return !s.Speaking })
+	if service.IsSpeaking() {
+		t.Error("IsSpeaking() = true after END")
+	}
+}
+
+func TestSpeechDispatcherPauseResume(t *testing.T) {
+	server, service, _, _ := startSpeechd(t)
+
+	if err := service.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	if !server.hasCommand("PAUSE self") {
+		t.Error("PAUSE self not sent")
+	}
+	server.event(ssipEventPaused, "PAUSED")
+	status := waitStatus(t, service, "paused", func(s TTSStatus) bool { return s.Paused })
+
+	// Waktu jeda tidak dihitung sebagai waktu bicara
+	time.Sleep(30 * time.Millisecond)
+	if elapsed := service.Status().ElapsedMs; elapsed > status.ElapsedMs+1 {
+		t.Errorf("elapsed grew while paused: %v -> %v", status.ElapsedMs, elapsed)
+	}
+
+	if err := service.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if !server.hasCommand("RESUME self") {
+		t.Error("RESUME self not sent")
+	}
+	server.event(ssipEventResumed, "RESUMED")
+	waitStatus(t, service, "resumed", func(s TTSStatus) bool { return !s.Paused && s.Speaking })
+
+	// Event PAUSED dari speech-dispatcher sendiri juga diikuti
+	server.event(ssipEventPaused, "PAUSED")
+	waitStatus(t, service, "paused by event", func(s TTSStatus) bool { return s.Paused })
+}
+
+func TestSpeechDispatcherSentenceJumps(t *testing.T) {
+	server, service, segments, ssml := startSpeechd(t)
+	_, sequence := firstSentenceMark(t, ssml)
+
+	server.event(ssipEventIndexMark, "INDEX MARK", fmt.Sprintf("%ds0", sequence))
+	if err := service.Pause(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.NextSentence(); err != nil {
+		t.Fatal(err)
+	}
+	ssml = server.waitSpeak()
+	mark, next := firstSentenceMark(t, ssml)
+	if next == sequence || mark != fmt.Sprintf("%ds1", next) {
+		t.Errorf("next sentence SSML starts at %q, want %ds1", mark, next)
+	}
+	if strings.Contains(ssml, "Selamat") {
+		t.Errorf("SSML after next still has the first sentence: %q", ssml)
+	}
+	status := service.Status()
+	if status.SentenceIndex != 1 || status.SentenceText != segments[1].Text || status.Paused {
+		t.Errorf("status after next = %+v, want sentence 1 playing", status)
+	}
+
+	// CANCELED dan mark dari pesan lama tidak mengubah posisi
+	server.eventFor(1, ssipEventCanceled, "CANCELED")
+	server.eventFor(1, ssipEventIndexMark, "INDEX MARK", fmt.Sprintf("%ds0", sequence))
+	server.event(ssipEventIndexMark, "INDEX MARK", fmt.Sprintf("%ds0", sequence))
+	server.event(ssipEventIndexMark, "INDEX MARK", fmt.Sprintf("%ds2", next))
+	status = waitStatus(t, service, "sentence 2", func(s TTSStatus) bool { return s.SentenceIndex == 2 })
+	if !status.Speaking {
+		t.Error("stale CANCELED event stopped the new message")
+	}
+
+	if err := service.NextSentence(); err != ErrNoSentence {
+		t.Errorf("NextSentence() at the end = %v, want ErrNoSentence", err)
+	}
+
+	if err := service.PreviousSentence(); err != nil {
+		t.Fatal(err)
+	}
+	ssml = server.waitSpeak()
+	if mark, previous := firstSentenceMark(t, ssml); mark != fmt.Sprintf("%ds1", previous) {
+		t.Errorf("previous sentence SSML starts at %q", mark)
+	}
+	if status := service.Status(); status.SentenceIndex != 1 {
+		t.Errorf("sentence after previous = %d, want 1", status.SentenceIndex)
+	}
+
+	if err := service.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if service.IsSpeaking() {
+		t.Error("IsSpeaking() = true after Stop")
+	}
+	if err := service.NextSentence(); err != ErrNotSpeaking {
+		t.Errorf("NextSentence() after Stop = %v, want ErrNotSpeaking", err)
+	}
+}
+
+func TestSpeechDispatcherVoices(t *testing.T) {
+	server := newFakeSSIP(t)
+	service := NewSpeechDispatcherService(server.socket, nil)
+
+	voices, err := service.GetVoices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(voices) != 2 || voices[0] != "ibu-ani" || voices[1] != "cmu-slt" {
+		t.Errorf("voices = %v, want [ibu-ani cmu-slt]", voices)
+	}
+	if !server.hasCommand("SET self CLIENT_NAME user:lansia:tts") {
+		t.Error("client name not set on connect")
+	}
+}
+
+func TestSpeechDispatcherUnreachable(t *testing.T) {
+	service := NewSpeechDispatcherService(filepath.Join(t.TempDir(), "missing.sock"), nil)
+	response, err := service.Speak("Halo.", TTSConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Success || !strings.Contains(response.Error, "speech-dispatcher not reachable") {
+		t.Errorf("Speak() = %+v, want a not reachable error", response)
+	}
+	if _, err := service.Synthesize(context.Background(), "Halo.", TTSConfig{}); err != ErrNotSupported {
+		t.Errorf("Synthesize() without render = %v, want ErrNotSupported", err)
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ssipTimeout batas menunggu jawaban speech-dispatcher untuk satu perintah
+const ssipTimeout = 5 * time.Second
+
+// ErrSSIPClosed dikembalikan jika koneksi ke speech-dispatcher terputus
+var ErrSSIPClosed = errors.New("speech-dispatcher connection closed")
+
+// Kode event SSIP (dikirim jika NOTIFICATION aktif)
+const (
+	ssipEventIndexMark = 700
+	ssipEventBegin     = 701
+	ssipEventEnd       = 702
+	ssipEventCanceled  = 703
+	ssipEventPaused    = 704
+	ssipEventResumed   = 705
+)
+
+// ssipMessage satu jawaban atau event SSIP. Baris "NNN-data" dikumpulkan di
+// Lines, baris terakhir "NNN teks" menjadi Text.
+type ssipMessage struct {
+	Code  int
+	Lines []string
+	Text  string
+}
+
+// ssipClient klien SSIP sederhana di atas Unix socket speech-dispatcher.
+//
+// Jawaban perintah dan event bercampur di satu stream. Event diteruskan ke
+// onEvent dari goroutine pembaca; jawaban diserahkan ke perintah yang sedang
+// menunggu, dan pembaca berhenti sampai perintah itu selesai memprosesnya,
+// supaya event sesudahnya (mis. END) tidak mendahului jawabannya.
+type ssipClient struct {
+	conn    net.Conn
+	cmdMu   sync.Mutex // Satu perintah dalam satu waktu
+	replies chan ssipMessage
+	ack     chan struct{}
+	closed  chan struct{}
+	once    sync.Once
+}
+
+// dialSSIP membuka koneksi ke socket dan mulai membaca stream
+func dialSSIP(socket string, onEvent func(ssipMessage)) (*ssipClient, error) {
+	conn, err := net.DialTimeout("unix", socket, ssipTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ssipClient{
+		conn:    conn,
+		replies: make(chan ssipMessage),
+		ack:     make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+	go c.readLoop(onEvent)
+	return c, nil
+}
+
+func (c *ssipClient) readLoop(onEvent func(ssipMessage)) {
+	defer c.Close()
+
+	reader := bufio.NewReader(c.conn)
+	message := ssipMessage{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) < 4 {
+			continue
+		}
+		code, err := strconv.Atoi(line[:3])
+		if err != nil {
+			continue
+		}
+
+		// "NNN-" berarti masih ada baris berikutnya
+		if line[3] == '-' {
+			message.Lines = append(message.Lines, line[4:])
+			continue
+		}
+		message.Code = code
+		message.Text = line[4:]
+
+		if code >= 700 && code < 800 {
+			onEvent(message)
+		} else {
+			select {
+			case c.replies <- message:
+			case <-c.closed:
+				return
+			}
+			select {
+			case <-c.ack:
+			case <-c.closed:
+				return
+			}
+		}
+		message = ssipMessage{}
+	}
+}
+
+// Command mengirim satu perintah dan mengembalikan jawabannya.
+// Jawaban selain 2xx dikembalikan sebagai error.
+func (c *ssipClient) Command(format string, args ...interface{}) (ssipMessage, error) {
+	c.cmdMu.Lock()
+	defer c.cmdMu.Unlock()
+	return c.roundTrip(fmt.Sprintf(format, args...)+"\r\n", nil)
+}
+
+// Speak mengirim teks dengan SPEAK. queued dipanggil dengan id pesan sebelum
+// event untuk pesan itu diproses.
+func (c *ssipClient) Speak(text string, queued func(msgID string)) error {
+	c.cmdMu.Lock()
+	defer c.cmdMu.Unlock()
+
+	if _, err := c.roundTrip("SPEAK\r\n", nil); err != nil {
+		return err
+	}
+
+	// Data diakhiri baris "."; baris yang diawali titik digandakan titiknya
+	var data strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r", ""), "\n") {
+		if strings.HasPrefix(line, ".") {
+			line = "." + line
+		}
+		data.WriteString(line + "\r\n")
+	}
+	data.WriteString(".\r\n")
+
+	_, err := c.roundTrip(data.String(), func(reply ssipMessage) {
+		if len(reply.Lines) > 0 {
+			queued(reply.Lines[0])
+		}
+	})
+	return err
+}
+
+// roundTrip menulis payload lalu menunggu jawaban; handle dijalankan sebelum
+// pembaca melanjutkan ke pesan berikutnya
+func (c *ssipClient) roundTrip(payload string, handle func(ssipMessage)) (ssipMessage, error) {
+	c.conn.SetWriteDeadline(time.Now().Add(ssipTimeout))
+	if _, err := c.conn.Write([]byte(payload)); err != nil {
+		c.Close()
+		return ssipMessage{}, ErrSSIPClosed
+	}
+
+	select {
+	case reply := <-c.replies:
+		if handle != nil && reply.Code/100 == 2 {
+			handle(reply)
+		}
+		select {
+		case c.ack <- struct{}{}:
+		case <-c.closed:
+		}
+		if reply.Code/100 != 2 {
+			return reply, fmt.Errorf("speech-dispatcher: %d %s", reply.Code, reply.Text)
+		}
+		return reply, nil
+	case <-time.After(ssipTimeout):
+		// Posisi stream tidak jelas lagi, koneksi ditutup
+		c.Close()
+		return ssipMessage{}, fmt.Errorf("speech-dispatcher did not answer")
+	case <-c.closed:
+		return ssipMessage{}, ErrSSIPClosed
+	}
+}
+
+// Closed mengecek apakah koneksi sudah terputus
+func (c *ssipClient) Closed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close menutup koneksi
+func (c *ssipClient) Close() {
+	c.once.Do(func() {
+		close(c.closed)
+		c.conn.Close()
+	})
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// firstStreamRunes potongan pertama dibuat pendek supaya audio pertama
+// cepat terdengar, berapapun panjang teksnya
+const firstStreamRunes = 80
+
+// streamLookahead jumlah potongan yang dirender lebih dulu selama
+// potongan sebelumnya masih dikirim
+const streamLookahead = 2
+
+// StreamChunk satu potongan audio hasil streaming
+type StreamChunk struct {
+	Index      int     `json:"index"`
+	Text       string  `json:"text"`
+	Start      int     `json:"start"` // Offset rune di teks asli
+	End        int     `json:"end"`
+	TimeMs     float64 `json:"time_ms"` // Posisi potongan di seluruh audio
+	DurationMs float64 `json:"audio_duration_ms"`
+	Marks      []Mark  `json:"marks,omitempty"`
+	Language   string  `json:"language,omitempty"` // Bahasa hasil deteksi (config.language auto)
+	Audio      []byte  `json:"-"`                  // WAV lengkap untuk potongan ini
+}
+
+// StreamSegments memecah teks per kalimat untuk streaming, dengan potongan
+// pertama dipecah lagi di koma/spasi jika terlalu panjang
+func StreamSegments(text string) []Segment {
+	segments := limitSegments(SplitSentences(text), speakSegmentRunes)
+	if len(segments) == 0 {
+		return segments
+	}
+	first := limitSegments(segments[:1], firstStreamRunes)
+	return append(first, segments[1:]...)
+}
+
+// StreamAudio merender teks per potongan dan memanggil emit untuk setiap
+// potongan segera setelah selesai. Potongan berikutnya sudah dirender
+// selama emit berjalan. Berhenti jika ctx dibatalkan atau emit gagal.
+func StreamAudio(ctx context.Context, service TTSService, text string, config TTSConfig, emit func(StreamChunk) error) error {
+	segments := StreamSegments(text)
+	if len(segments) == 0 {
+		return fmt.Errorf("text cannot be empty")
+	}
+
+	// Bahasa ditebak dari seluruh teks, supaya kalimat pendek ikut bahasa
+	// kalimat di sekitarnya
+	var languages []LanguageSegment
+	fallback := config.Language
+	if autoLanguage(config) {
+		languages = DetectSegments(text)
+		fallback, _ = DetectLanguage(text)
+	}
+	languageOf := func(segment Segment) string {
+		return segmentLanguage(languages, segment.Start, fallback)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type rendered struct {
+		audio *AudioResult
+		err   error
+	}
+	results := make(chan rendered, streamLookahead)
+	go func() {
+		defer close(results)
+		for _, segment := range segments {
+			segmentConfig := config
+			segmentConfig.Language = languageOf(segment)
+			audio, err := service.Synthesize(ctx, segment.Text, segmentConfig)
+			select {
+			case results <- rendered{audio, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// Semua potongan dikirim dengan format potongan pertama; bagian bahasa
+	// lain bisa dirender engine lain dengan sample rate berbeda
+	var format wavFormat
+	offsetMs := 0.0
+	for index, segment := range segments {
+		var result rendered
+		select {
+		case r, ok := <-results:
+			if !ok {
+				return ctx.Err()
+			}
+			result = r
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if result.err != nil {
+			return fmt.Errorf("chunk %d: %v", index+1, result.err)
+		}
+
+		audio := result.audio.Data
+		var err error
+		if index == 0 {
+			format, _, err = parseWAV(audio)
+		} else {
+			audio, err = convertWAV(audio, format)
+		}
+		if err != nil {
+			return fmt.Errorf("chunk %d: %v", index+1, err)
+		}
+		duration := wavDurationMs(audio)
+
+		// Mark dari Synthesize relatif terhadap potongan, geser ke teks dan audio penuh
+		marks := make([]Mark, len(result.audio.Marks))
+		for i, mark := range result.audio.Marks {
+			mark.Start += segment.Start
+			mark.End += segment.Start
+			mark.TimeMs += offsetMs
+			marks[i] = mark
+		}
+
+		chunk := StreamChunk{
+			Index:      index,
+			Text:       segment.Text,
+			Start:      segment.Start,
+			End:        segment.End,
+			TimeMs:     offsetMs,
+			DurationMs: duration,
+			Marks:      marks,
+			Audio:      audio,
+		}
+		if autoLanguage(config) {
+			chunk.Language = languageOf(segment)
+		}
+		if err := emit(chunk); err != nil {
+			return err
+		}
+		offsetMs += duration
+	}
+	return nil
+}
+
+// WAVStreamWriter menulis beberapa WAV sebagai satu stream WAV berformat WAV
+// pertama. Header ditulis sekali dengan ukuran "tidak diketahui" seperti
+// espeak --stdout.
+type WAVStreamWriter struct {
+	w       io.Writer
+	format  wavFormat
+	started bool
+}
+
+func NewWAVStreamWriter(w io.Writer) *WAVStreamWriter {
+	return &WAVStreamWriter{w: w}
+}
+
+// WriteWAV menambahkan data PCM dari satu file WAV ke stream
+func (s *WAVStreamWriter) WriteWAV(data []byte) error {
+	format, pcm, err := parseWAV(data)
+	if err != nil {
+		return err
+	}
+
+	if s.started && format != s.format {
+		// Potongan dari engine lain diubah ke format awal stream
+		if data, err = convertWAV(data, s.format); err != nil {
+			return err
+		}
+		format, pcm, _ = parseWAV(data)
+	}
+
+	if !s.started {
+		header := encodeWAV(format, nil)
+		binary.LittleEndian.PutUint32(header[4:8], 0xFFFFFFFF)
+		binary.LittleEndian.PutUint32(header[40:44], 0xFFFFFFFF)
+		if _, err := s.w.Write(header); err != nil {
+			return err
+		}
+		s.format = format
+		s.started = true
+	}
+
+	_, err = s.w.Write(pcm)
+	return err
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+)
+
+// rateTTSService merender setiap teks sebagai WAV dengan sample rate menurut
+// bahasa, seperti voice bahasa lain yang dirender engine lain
+type rateTTSService struct {
+	TTSService
+	rates map[string]uint32
+}
+
+func (s rateTTSService) Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error) {
+	rate := s.rates[config.Language]
+	data := testWAV(rate, int(rate)/10)
+	return &AudioResult{Data: data, ContentType: "audio/wav", AudioDuration: wavDurationMs(data)}, nil
+}
+
+func TestStreamAudioKeepsFirstChunkFormat(t *testing.T) {
+	service := rateTTSService{rates: map[string]uint32{"id-ID": 22050, "en-US": 16000}}
+	text := "Selamat pagi, jangan lupa minum obat setelah sarapan ya. " +
+		"Please take one tablet after breakfast with a glass of water. " +
+		"Semoga lekas sembuh dan tetap semangat menjalani hari ini."
+
+	var stream bytes.Buffer
+	writer := NewWAVStreamWriter(&stream)
+	chunks := []StreamChunk{}
+	err := StreamAudio(context.Background(), service, text, TTSConfig{Language: LanguageAuto}, func(chunk StreamChunk) error {
+		chunks = append(chunks, chunk)
+		return writer.WriteWAV(chunk.Audio)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(chunks) != 3 || chunks[1].Language != "en-US" {
+		t.Fatalf("chunks = %d, second language %q; want 3 with English in the middle", len(chunks), chunks[1].Language)
+	}
+	for _, chunk := range chunks {
+		format, _, err := parseWAV(chunk.Audio)
+		if err != nil {
+			t.Fatalf("chunk %d: %v", chunk.Index, err)
+		}
+		if format.SampleRate != 22050 {
+			t.Errorf("chunk %d (%s) sample rate = %d, want 22050", chunk.Index, chunk.Language, format.SampleRate)
+		}
+		if chunk.DurationMs < 99 || chunk.DurationMs > 101 {
+			t.Errorf("chunk %d duration = %v ms, want 100", chunk.Index, chunk.DurationMs)
+		}
+	}
+	if chunks[2].TimeMs != chunks[0].DurationMs+chunks[1].DurationMs {
+		t.Errorf("third chunk at %v ms, want after the first two", chunks[2].TimeMs)
+	}
+
+	header := stream.Bytes()[:44]
+	if rate := binary.LittleEndian.Uint32(header[24:28]); rate != 22050 {
+		t.Errorf("stream sample rate = %d, want 22050", rate)
+	}
+	if samples := (stream.Len() - 44) / 2; samples < 3*2204 || samples > 3*2205 {
+		t.Errorf("stream has %d samples, want about 3 x 2205", samples)
+	}
+}
+
+func TestWAVStreamWriterConvertsFormat(t *testing.T) {
+	var stream bytes.Buffer
+	writer := NewWAVStreamWriter(&stream)
+	if err := writer.WriteWAV(testWAV(16000, 1600)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteWAV(testWAV(8000, 800)); err != nil {
+		t.Fatalf("second WAV with another sample rate: %v", err)
+	}
+	if samples := (stream.Len() - 44) / 2; samples != 3200 {
+		t.Errorf("stream has %d samples, want 3200 at 16 kHz", samples)
+	}
+}
+
+func TestConvertWAV(t *testing.T) {
+	stereo := wavFormat{AudioFormat: 1, Channels: 2, SampleRate: 16000, ByteRate: 64000, BlockAlign: 4, BitsPerSample: 16}
+	data, err := convertWAV(testWAV(8000, 80), stereo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	format, pcm, err := parseWAV(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != stereo || len(pcm) != 160*4 {
+		t.Errorf("format = %+v with %d bytes, want stereo 16 kHz with 640 bytes", format, len(pcm))
+	}
+	if !bytes.Equal(pcm[0:2], pcm[2:4]) {
+		t.Error("channels differ, want the mono sample copied to both")
+	}
+
+	same := testWAV(8000, 80)
+	if converted, _ := convertWAV(same, stereo); bytes.Equal(converted, same) {
+		t.Error("convertWAV returned the input for a different format")
+	}
+	mono, _, _ := parseWAV(same)
+	if converted, err := convertWAV(same, mono); err != nil || !bytes.Equal(converted, same) {
+		t.Error("convertWAV changed a WAV that already has the format")
+	}
+}
@@ -4,28 +4,39 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http" // Added for CloudTTSService
 	"os/exec"
-	"runtime"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
+// VolumeDefault menandai volume yang tidak diisi; applyConfigDefaults
+// menggantinya dengan 1.0. Volume 0 berarti bisu, bukan default.
+const VolumeDefault = -1.0
+
 // TTSConfig konfigurasi untuk text-to-speech
 type TTSConfig struct {
     Language    string  `json:"language"`
     Speed       float64 `json:"speed"`       // 0.5 - 2.0
-    Volume      float64 `json:"volume"`      // 0.0 - 1.0
+    Volume      float64 `json:"volume"`      // 0.0 (bisu) - 1.0, VolumeDefault jika tidak diisi
     Voice       string  `json:"voice"`       // Nama voice tertentu
+    Engine      string  `json:"engine"`      // Engine yang diutamakan (lihat /api/voices)
     UseSystemTTS bool   `json:"use_system_tts"` // Gunakan sistem atau cloud
+    Site        string  `json:"site"`        // Hostname halaman asal teks, untuk entri leksikon per situs
+    ReadAs      string  `json:"read_as"`     // Kosong (otomatis), digits atau spell
+    Verbosity   string  `json:"verbosity"`   // Pembacaan simbol dan emoji: minimal, normal (default) atau verbose
+
+    lexicon   *lexiconRules     // Diisi LexiconTTSService
+    languages []LanguageSegment // Diisi LanguageTTSService untuk teks campuran
 }
 
 // TTSRequest request untuk text-to-speech
 type TTSRequest struct {
     Text     string   `json:"text"`
     Config   TTSConfig `json:"config"`
-    ClientID string   `json:"client_id"` // Untuk tracking
+    ClientID string   `json:"client_id"` // Tab/frame pemilik request (untuk antrean)
+    Priority Priority `json:"priority"`  // interrupt, queue atau drop
 }
 
 // TTSResponse response dari text-to-speech
@@ -37,40 +48,74 @@ type TTSResponse struct {
     Error      string    `json:"error,omitempty"`
 }
 
+// AudioResult hasil sintesis yang dikembalikan ke client (bukan diputar di host)
+type AudioResult struct {
+    Data          []byte  `json:"-"`
+    ContentType   string  `json:"content_type"`
+    AudioDuration float64 `json:"audio_duration_ms,omitempty"` // Panjang audio
+    Duration      float64 `json:"duration_ms,omitempty"`       // Lama proses render
+    Marks         []Mark  `json:"marks,omitempty"`             // Timing kata dan kalimat
+    Cached        bool    `json:"cached,omitempty"`            // Diambil dari AudioCache
+    Engine        string  `json:"engine,omitempty"`            // Engine yang merender
+    Fallback      bool    `json:"fallback,omitempty"`          // Dirender service cadangan (mis. cloud gagal)
+    Language      string  `json:"language,omitempty"`          // Bahasa hasil deteksi (config.language auto)
+    Languages     []LanguageSegment `json:"language_segments,omitempty"` // Bagian per bahasa, jika teks campuran
+}
+
 // TTSService interface untuk TTS
 type TTSService interface {
     Speak(text string, config TTSConfig) (*TTSResponse, error)
+    Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error)
     Stop() error
+    Pause() error
+    Resume() error
+    Status() TTSStatus
     GetVoices() ([]string, error)
     IsSpeaking() bool
 }
 
 // SystemTTSService implementasi menggunakan sistem TTS
 type SystemTTSService struct {
+    engines   *EngineRegistry
     mu        sync.Mutex
     isSpeaking bool
     currentCmd *exec.Cmd
     ctx        context.Context
     cancel     context.CancelFunc
+
+    // Info utterance yang sedang diputar, untuk Status() dan navigasi kalimat
+    current   *TTSStatus
+    segments  []Segment
+    config    TTSConfig
+    pausedAt  time.Time
+    pausedFor time.Duration
+
+    // Waktu kalimat aktif, untuk menghitung progress
+    segmentStartedAt time.Time
+    segmentPausedFor time.Duration
 }
 
-// NewSystemTTSService membuat instance baru SystemTTSService
-func NewSystemTTSService() *SystemTTSService {
+// NewSystemTTSService membuat instance baru SystemTTSService.
+// Engine dipilih dari registry untuk setiap kalimat dan render.
+func NewSystemTTSService(engines *EngineRegistry) *SystemTTSService {
     ctx, cancel := context.WithCancel(context.Background())
     return &SystemTTSService{
-        ctx:    ctx,
-        cancel: cancel,
+        engines: engines,
+        ctx:     ctx,
+        cancel:  cancel,
     }
 }
 
-// Speak mengonversi teks ke suara menggunakan sistem TTS
+// Speak mengonversi teks ke suara menggunakan sistem TTS.
+// Teks dipecah per kalimat dan diputar berurutan di background.
 func (s *SystemTTSService) Speak(text string, config TTSConfig) (*TTSResponse, error) {
     s.mu.Lock()
     defer s.mu.Unlock()
 
     // Stop speech sebelumnya jika sedang berjalan
+    // (pakai stopLocked karena s.mu sudah dipegang)
     if s.isSpeaking {
-        s.Stop()
+        s.stopLocked()
     }
 
     // Validasi input
@@ -82,71 +127,53 @@ func (s *SystemTTSService) Speak(text string, config TTSConfig) (*TTSResponse, e
         }, nil
     }
 
-    if len(text) > 5000 {
+    if utf8.RuneCountInString(text) > MaxTextRunes {
         return &TTSResponse{
             Success:   false,
-            Error:     "Text too long (max 5000 characters)",
+            Error:     fmt.Sprintf("Text too long (max %d characters)", MaxTextRunes),
             Timestamp: time.Now(),
         }, nil
     }
 
     // Set default config jika kosong
-    if config.Language == "" {
-        config.Language = "id-ID"
-    }
-    if config.Speed == 0 {
-        config.Speed = 1.0
-    }
-    if config.Volume == 0 {
-        config.Volume = 1.0
-    }
+    config = applyConfigDefaults(config)
 
     // Mulai timer untuk menghitung durasi
     startTime := time.Now()
 
-    // Eksekusi perintah TTS berdasarkan OS
-    var cmd *exec.Cmd
-    switch runtime.GOOS {
-    case "darwin": // macOS
-        cmd = s.getMacOSTTSCommand(text, config)
-    case "linux":
-        cmd = s.getLinuxTTSCommand(text, config)
-    case "windows":
-        cmd = s.getWindowsTTSCommand(text, config)
-    default:
+    segments := limitSegments(SplitSentences(text), speakSegmentRunes)
+    if len(segments) == 0 {
         return &TTSResponse{
             Success:   false,
-            Error:     fmt.Sprintf("Unsupported OS: %s", runtime.GOOS),
+            Error:     "Text is empty",
             Timestamp: time.Now(),
         }, nil
     }
 
-    if cmd == nil {
+    s.segments = segments
+    s.config = config
+    s.pausedFor = 0
+    s.current = &TTSStatus{
+        Speaking:            true,
+        Text:                text,
+        Language:            config.Language,
+        StartedAt:           &startTime,
+        EstimatedDurationMs: estimateSpeechMs(text, config.Speed),
+        SentenceCount:       len(segments),
+    }
+
+    // Kalimat pertama dijalankan langsung supaya error engine
+    // (mis. espeak tidak terpasang) bisa dilaporkan ke pemanggil
+    if err := s.playSegmentLocked(0); err != nil {
+        s.resetLocked()
         return &TTSResponse{
             Success:   false,
-            Error:     "Failed to create TTS command",
+            Error:     err.Error(),
             Timestamp: time.Now(),
         }, nil
     }
-
-    s.currentCmd = cmd
     s.isSpeaking = true
 
-    // Jalankan perintah dalam goroutine
-    go func() {
-        defer func() {
-            s.mu.Lock()
-            s.isSpeaking = false
-            s.currentCmd = nil
-            s.mu.Unlock()
-        }()
-
-        err := cmd.Run()
-        if err != nil {
-            log.Printf("TTS error: %v", err)
-        }
-    }()
-
     duration := time.Since(startTime).Seconds() * 1000
 
     return &TTSResponse{
@@ -156,293 +183,289 @@ func (s *SystemTTSService) Speak(text string, config TTSConfig) (*TTSResponse, e
     }, nil
 }
 
-// getMacOSTTSCommand membuat command TTS untuk macOS
-func (s *SystemTTSService) getMacOSTTSCommand(text string, config TTSConfig) *exec.Cmd {
-    // Gunakan 'say' command dengan parameter
-    args := []string{}
-    
-    // Pilih voice berdasarkan bahasa
-    switch config.Language {
-    case "id-ID", "id":
-        args = append(args, "-v", "Damayanti") // Voice Indonesia
-    case "en-US", "en":
-        args = append(args, "-v", "Alex") // Voice English US
-    default:
-        args = append(args, "-v", "Damayanti")
+// startSegment membuat dan menjalankan command untuk satu kalimat
+func (s *SystemTTSService) startSegment(ctx context.Context, text string, config TTSConfig) (*exec.Cmd, error) {
+    // Proses dijalankan dalam process group sendiri supaya bisa di-pause
+    return s.engines.startSpeech(ctx, prepareText(text, config), config, setProcessGroup)
+}
+
+// playSegmentLocked memutar kalimat ke-index dari utterance aktif
+func (s *SystemTTSService) playSegmentLocked(index int) error {
+    segment := s.segments[index]
+    // Teks campuran dibaca dengan voice bahasa kalimatnya
+    config := s.config
+    config.Language = segmentLanguage(config.languages, segment.Start, config.Language)
+    cmd, err := s.startSegment(s.ctx, segment.Text, config)
+    if err != nil {
+        return err
     }
-    
-    // Speed rate (say command menggunakan rate dalam WPM)
-    // Default rate adalah 175 WPM, adjust berdasarkan config.Speed
-    rate := int(175 * config.Speed)
-    if rate < 50 {
-        rate = 50
+
+    s.currentCmd = cmd
+    s.segmentStartedAt = time.Now()
+    s.segmentPausedFor = 0
+    s.current.SentenceIndex = index
+    s.current.SentenceText = segment.Text
+    s.current.SentenceStart = segment.Start
+    s.current.SentenceEnd = segment.End
+
+    // Tunggu kalimat selesai dalam goroutine, lalu lanjut ke kalimat berikutnya
+    go s.waitSegment(cmd, index)
+    return nil
+}
+
+// waitSegment menunggu satu kalimat selesai lalu memutar kalimat berikutnya
+func (s *SystemTTSService) waitSegment(cmd *exec.Cmd, index int) {
+    err := cmd.Wait()
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    // Sudah di-stop, dinavigasi, atau diganti utterance lain
+    if s.currentCmd != cmd {
+        return
     }
-    if rate > 400 {
-        rate = 400
+
+    if err != nil {
+        log.Printf("TTS error: %v", err)
+        s.resetLocked()
+        return
     }
-    args = append(args, "-r", fmt.Sprintf("%d", rate))
-    
-    // Volume (0-100)
-    volume := int(config.Volume * 100)
-    if volume > 0 {
-        args = append(args, "-a", fmt.Sprintf("%d", volume))
+
+    next := index + 1
+    if next >= len(s.segments) {
+        s.resetLocked()
+        return
+    }
+
+    if err := s.playSegmentLocked(next); err != nil {
+        log.Printf("TTS error: %v", err)
+        s.resetLocked()
     }
-    
-    // Tambahkan teks
-    args = append(args, text)
-    
-    return exec.CommandContext(s.ctx, "say", args...)
 }
 
-// getLinuxTTSCommand membuat command TTS untuk Linux
-func (s *SystemTTSService) getLinuxTTSCommand(text string, config TTSConfig) *exec.Cmd {
-    // Coba espeak dulu, lalu festival
-    var cmd *exec.Cmd
-    
-    // Check if espeak is available
-    if _, err := exec.LookPath("espeak"); err == nil {
-        args := []string{}
-        
-        // Language
-        switch config.Language {
-        case "id-ID", "id":
-            args = append(args, "-v", "id") // Indonesian
-        case "en-US", "en":
-            args = append(args, "-v", "en-us")
-        default:
-            args = append(args, "-v", "id")
-        }
-        
-        // Speed (espeak default 175, range 80-450)
-        speed := int(175 * config.Speed)
-        if speed < 80 {
-            speed = 80
+// NextSentence melompat ke kalimat berikutnya
+func (s *SystemTTSService) NextSentence() error {
+    return s.jumpSentence(1)
+}
+
+// PreviousSentence kembali ke kalimat sebelumnya (atau mengulang kalimat pertama)
+func (s *SystemTTSService) PreviousSentence() error {
+    return s.jumpSentence(-1)
+}
+
+// RepeatSentence mengulang kalimat yang sedang diputar dari awal
+func (s *SystemTTSService) RepeatSentence() error {
+    return s.jumpSentence(0)
+}
+
+func (s *SystemTTSService) jumpSentence(delta int) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if s.current == nil || s.currentCmd == nil {
+        return ErrNotSpeaking
+    }
+
+    index := s.current.SentenceIndex + delta
+    if index < 0 {
+        index = 0
+    }
+    if index >= len(s.segments) {
+        return ErrNoSentence
+    }
+
+    // Navigasi juga melanjutkan speech yang sedang dijeda
+    if s.current.Paused {
+        s.pausedFor += time.Since(s.pausedAt)
+        s.current.Paused = false
+    }
+
+    // Hentikan kalimat lama; goroutine-nya akan melihat currentCmd sudah berganti
+    old := s.currentCmd
+    s.currentCmd = nil
+    if old.Cancel != nil {
+        old.Cancel()
+    }
+
+    if err := s.playSegmentLocked(index); err != nil {
+        s.resetLocked()
+        return err
+    }
+    return nil
+}
+
+// Synthesize merender teks menjadi WAV tanpa memutarnya di host, dengan
+// engine pertama di rantai fallback registry yang berhasil
+func (s *SystemTTSService) Synthesize(ctx context.Context, text string, config TTSConfig) (*AudioResult, error) {
+    return s.engines.Synthesize(ctx, text, config)
+}
+
+// synthesizeChunks memecah teks per kalimat, merender tiap potongan (teks yang
+// sudah dibersihkan dan dinormalisasi) dengan render, lalu menggabungkan WAV
+// dan timing-nya. Render berhenti jika ctx dibatalkan.
+func synthesizeChunks(ctx context.Context, text string, config TTSConfig, render func(index int, chunk string) ([]byte, error)) (*AudioResult, error) {
+    if err := validateSynthesisText(text); err != nil {
+        return nil, err
+    }
+
+    startTime := time.Now()
+
+    parts := [][]byte{}
+    marks := []Mark{}
+    offsetMs := 0.0
+    for i, chunk := range ChunkText(text, defaultChunkRunes) {
+        if err := ctx.Err(); err != nil {
+            return nil, err
         }
-        if speed > 450 {
-            speed = 450
+        data, err := render(i, prepareText(chunk.Text, config))
+        if err != nil {
+            return nil, err
         }
-        args = append(args, "-s", fmt.Sprintf("%d", speed))
-        
-        // Volume (0-200, default 100)
-        volume := int(config.Volume * 100)
-        args = append(args, "-a", fmt.Sprintf("%d", volume))
-        
-        // Pitch (30-99, default 50)
-        pitch := 50
-        args = append(args, "-p", fmt.Sprintf("%d", pitch))
-        
-        args = append(args, text)
-        cmd = exec.CommandContext(s.ctx, "espeak", args...)
-        
-    } else if _, err := exec.LookPath("festival"); err == nil {
-        // Use festival with pipe
-        script := fmt.Sprintf(`(SayText "%s")`, text)
-        cmd = exec.CommandContext(s.ctx, "sh", "-c", fmt.Sprintf("echo '%s' | festival --tts", script))
-    } else {
-        log.Println("No TTS engine found on Linux (espeak or festival)")
-        return nil
+        parts = append(parts, data)
+
+        // Timing kata/kalimat, offset mengacu ke teks asli
+        marks = append(marks, marksForAudio(chunk, data, offsetMs)...)
+        offsetMs += wavDurationMs(data)
     }
-    
-    return cmd
-}
 
-// getWindowsTTSCommand membuat command TTS untuk Windows
-func (s *SystemTTSService) getWindowsTTSCommand(text string, config TTSConfig) *exec.Cmd {
-    // Gunakan PowerShell dengan System.Speech
-    script := fmt.Sprintf(`
-    Add-Type -AssemblyName System.speech
-    $speak = New-Object System.Speech.Synthesis.SpeechSynthesizer
-    
-    # Set language
-    $culture = New-Object System.Globalization.CultureInfo("%s")
-    try {
-        $speak.SelectVoiceByHints([System.Speech.Synthesis.VoiceGender]::Female, 
-                                   [System.Speech.Synthesis.VoiceAge]::Adult,
-                                   0, $culture)
-    } catch {
-        # Use default voice if specific language not found
+    data := parts[0]
+    if len(parts) > 1 {
+        var err error
+        if data, err = concatWAV(parts); err != nil {
+            return nil, err
+        }
     }
-    
-    # Set rate (-10 to 10)
-    $rate = %d
-    if ($rate -lt -10) { $rate = -10 }
-    if ($rate -gt 10) { $rate = 10 }
-    $speak.Rate = $rate
-    
-    # Set volume (0-100)
-    $volume = %d
-    if ($volume -lt 0) { $volume = 0 }
-    if ($volume -gt 100) { $volume = 100 }
-    $speak.Volume = $volume
-    
-    # Speak
-    $speak.Speak("%s")
-    `, 
-    config.Language,
-    int((config.Speed-1)*10), // Convert 0.5-2.0 to -5 to 10
-    int(config.Volume*100),
-    escapePowerShellString(text))
-    
-    return exec.CommandContext(s.ctx, "powershell", "-Command", script)
-}
 
-// escapePowerShellString escape string untuk PowerShell
-func escapePowerShellString(s string) string {
-    // Escape quotes dan karakter khusus
-    s = strings.ReplaceAll(s, `"`, `\"`)
-    s = strings.ReplaceAll(s, "`", "``")
-    s = strings.ReplaceAll(s, "$", "`$")
-    return s
+    return &AudioResult{
+        Data:          data,
+        ContentType:   "audio/wav",
+        AudioDuration: wavDurationMs(data),
+        Duration:      time.Since(startTime).Seconds() * 1000,
+        Marks:         marks,
+    }, nil
 }
 
 // Stop menghentikan speech yang sedang berjalan
 func (s *SystemTTSService) Stop() error {
     s.mu.Lock()
     defer s.mu.Unlock()
-    
-    if s.currentCmd != nil && s.currentCmd.Process != nil {
-        // Cancel context
+    return s.stopLocked()
+}
+
+// stopLocked sama dengan Stop, tapi s.mu harus sudah dipegang pemanggil
+func (s *SystemTTSService) stopLocked() error {
+    if s.currentCmd != nil {
+        // Cancel context; CommandContext akan membunuh prosesnya,
+        // termasuk proses yang belum sempat start
         if s.cancel != nil {
             s.cancel()
         }
         
-        // Kill process
-        err := s.currentCmd.Process.Kill()
-        if err != nil {
-            return fmt.Errorf("failed to stop TTS: %v", err)
-        }
-        
         // Reset context
         ctx, cancel := context.WithCancel(context.Background())
         s.ctx = ctx
         s.cancel = cancel
     }
     
+    s.resetLocked()
+    return nil
+}
+
+// resetLocked menghapus state utterance aktif
+func (s *SystemTTSService) resetLocked() {
     s.isSpeaking = false
     s.currentCmd = nil
-    return nil
+    s.current = nil
+    s.segments = nil
 }
 
-// GetVoices mendapatkan daftar voice yang tersedia
-func (s *SystemTTSService) GetVoices() ([]string, error) {
-    switch runtime.GOOS {
-    case "darwin": // macOS
-        cmd := exec.Command("say", "-v", "?")
-        output, err := cmd.Output()
-        if err != nil {
-            return nil, err
-        }
-        
-        voices := []string{}
-        lines := strings.Split(string(output), "\n")
-        for _, line := range lines {
-            if strings.Contains(line, "#") {
-                continue
-            }
-            parts := strings.Fields(line)
-            if len(parts) > 0 {
-                voices = append(voices, parts[0])
-            }
-        }
-        return voices, nil
-        
-    case "windows":
-        // Windows - bisa menggunakan PowerShell untuk mendapatkan voices
-        script := `
-        Add-Type -AssemblyName System.speech
-        $speak = New-Object System.Speech.Synthesis.SpeechSynthesizer
-        $speak.GetInstalledVoices() | ForEach-Object {
-            $_.VoiceInfo.Name
-        }
-        `
-        cmd := exec.Command("powershell", "-Command", script)
-        output, err := cmd.Output()
-        if err != nil {
-            return nil, err
-        }
-        return strings.Split(strings.TrimSpace(string(output)), "\n"), nil
-        
-    case "linux":
-        // Linux - espeak voices
-        cmd := exec.Command("espeak", "--voices")
-        output, err := cmd.Output()
-        if err != nil {
-            return nil, err
-        }
-        
-        voices := []string{}
-        lines := strings.Split(string(output), "\n")
-        for i, line := range lines {
-            if i == 0 {
-                continue // Skip header
-            }
-            parts := strings.Fields(line)
-            if len(parts) > 1 {
-                voices = append(voices, parts[1]) // Language code
-            }
-        }
-        return voices, nil
+// Pause menjeda speech yang sedang berjalan (SIGSTOP pada proses engine)
+func (s *SystemTTSService) Pause() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if s.current == nil || s.currentCmd == nil || s.currentCmd.Process == nil {
+        return ErrNotSpeaking
     }
-    
-    return []string{"default"}, nil
+    if s.current.Paused {
+        return nil
+    }
+
+    if err := suspendProcess(s.currentCmd); err != nil {
+        return err
+    }
+    s.current.Paused = true
+    s.pausedAt = time.Now()
+    return nil
 }
 
-// IsSpeaking mengecek apakah sedang ada speech yang berjalan
-func (s *SystemTTSService) IsSpeaking() bool {
+// Resume melanjutkan speech yang sedang dijeda
+func (s *SystemTTSService) Resume() error {
     s.mu.Lock()
     defer s.mu.Unlock()
-    return s.isSpeaking
-}
 
-// CloudTTSService implementasi menggunakan Google Cloud TTS (opsional)
-type CloudTTSService struct {
-    apiKey     string
-    httpClient *http.Client
-}
+    if s.current == nil || s.currentCmd == nil || s.currentCmd.Process == nil {
+        return ErrNotSpeaking
+    }
+    if !s.current.Paused {
+        return nil
+    }
 
-// NewCloudTTSService membuat instance baru CloudTTSService
-func NewCloudTTSService(apiKey string) *CloudTTSService {
-    return &CloudTTSService{
-        apiKey: apiKey,
-        httpClient: &http.Client{
-            Timeout: 30 * time.Second,
-        },
+    if err := resumeProcess(s.currentCmd); err != nil {
+        return err
     }
+    s.current.Paused = false
+    s.pausedFor += time.Since(s.pausedAt)
+    s.segmentPausedFor += time.Since(s.pausedAt)
+    return nil
 }
 
-// Speak menggunakan Google Cloud Text-to-Speech API
-func (c *CloudTTSService) Speak(text string, config TTSConfig) (*TTSResponse, error) {
-    // Implementasi Cloud TTS (Google, AWS, Azure)
-    // Untuk sekarang, return error atau fallback ke system TTS
-    return nil, fmt.Errorf("Cloud TTS not implemented. Please use SystemTTSService")
-}
+// Status melaporkan utterance yang sedang diputar beserta progress-nya
+func (s *SystemTTSService) Status() TTSStatus {
+    s.mu.Lock()
+    defer s.mu.Unlock()
 
-// Stop menghentikan speech (tidak ada implementasi untuk cloud)
-func (c *CloudTTSService) Stop() error {
-    // Cloud TTS biasanya streaming, tidak bisa di-stop
-    return nil
+    if s.current == nil {
+        return TTSStatus{}
+    }
+
+    status := *s.current
+    // Waktu selama dijeda tidak dihitung sebagai progress
+    elapsed := time.Since(*status.StartedAt) - s.pausedFor
+    segmentElapsed := time.Since(s.segmentStartedAt) - s.segmentPausedFor
+    if status.Paused {
+        elapsed -= time.Since(s.pausedAt)
+        segmentElapsed -= time.Since(s.pausedAt)
+    }
+    status.ElapsedMs = elapsed.Seconds() * 1000
+
+    // Progress dihitung dari posisi kalimat di teks, ditambah
+    // perkiraan seberapa jauh kalimat aktif sudah diucapkan
+    total := utf8.RuneCountInString(status.Text)
+    if total > 0 {
+        segmentMs := estimateSpeechMs(status.SentenceText, s.config.Speed)
+        fraction := estimateProgress(segmentElapsed.Seconds()*1000, segmentMs)
+        position := float64(status.SentenceStart) + fraction*float64(status.SentenceEnd-status.SentenceStart)
+        status.Progress = position / float64(total)
+        status.Word = currentWord(s.segments[status.SentenceIndex], fraction*segmentMs, segmentMs)
+    }
+    return status
 }
 
-// GetVoices mendapatkan daftar voices dari cloud service
-func (c *CloudTTSService) GetVoices() ([]string, error) {
-    // Untuk Google Cloud TTS, perlu implementasi API call
-    // Return default voices untuk sekarang
-    return []string{
-        "id-ID-Standard-A",    // Female Indonesian
-        "id-ID-Standard-B",    // Male Indonesian
-        "id-ID-Standard-C",    // Male Indonesian 2
-        "id-ID-Standard-D",    // Female Indonesian 2
-        "en-US-Standard-A",    // Female English US
-        "en-US-Standard-B",    // Male English US
-        "en-US-Standard-C",    // Female English US 2
-        "en-US-Standard-D",    // Male English US 2
-    }, nil
+// GetVoices mendapatkan daftar voice dari semua engine yang terpasang
+func (s *SystemTTSService) GetVoices() ([]string, error) {
+    voices := []string{}
+    for _, voice := range s.engines.Voices() {
+        voices = append(voices, voice.Name)
+    }
+    return voices, nil
 }
 
 // IsSpeaking mengecek apakah sedang ada speech yang berjalan
-func (c *CloudTTSService) IsSpeaking() bool {
-    // Cloud TTS biasanya stateless
-    return false
+func (s *SystemTTSService) IsSpeaking() bool {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.isSpeaking
 }
 
 // Utility functions untuk TTS
@@ -453,11 +476,28 @@ func ValidateText(text string) (string, error) {
         return "", fmt.Errorf("text cannot be empty")
     }
     
-    // Batasi panjang teks
-    if len(text) > 5000 {
-        text = text[:5000] + "..."
+    // Batasi panjang teks (dihitung per rune supaya
+    // karakter multi-byte tidak terpotong di tengah)
+    if runes := []rune(text); len(runes) > MaxTextRunes {
+        text = string(runes[:MaxTextRunes]) + "..."
     }
     
+    return cleanText(text), nil
+}
+
+// validateSynthesisText mengecek teks sebelum dirender
+func validateSynthesisText(text string) error {
+    if strings.TrimSpace(text) == "" {
+        return fmt.Errorf("text cannot be empty")
+    }
+    if utf8.RuneCountInString(text) > MaxTextRunes {
+        return fmt.Errorf("text too long (max %d characters)", MaxTextRunes)
+    }
+    return nil
+}
+
+// cleanText membersihkan karakter yang bermasalah untuk engine TTS
+func cleanText(text string) string {
     // Bersihkan karakter khusus yang mungkin bermasalah
     text = strings.ReplaceAll(text, "\"", "'")
     text = strings.ReplaceAll(text, "\n", ". ")
@@ -467,7 +507,27 @@ func ValidateText(text string) (string, error) {
     // Multiple spaces to single space
     text = strings.Join(strings.Fields(text), " ")
     
-    return text, nil
+    return text
+}
+
+// prepareText menyiapkan teks untuk engine: dibersihkan, entri leksikon
+// dipasang, lalu angka, tanggal, jam dan singkatan diubah menjadi kata sesuai bahasa
+func prepareText(text string, config TTSConfig) string {
+    return normalizeText(cleanText(text), config)
+}
+
+// applyConfigDefaults mengisi field config yang kosong dengan nilai default
+func applyConfigDefaults(config TTSConfig) TTSConfig {
+    if config.Language == "" {
+        config.Language = "id-ID"
+    }
+    if config.Speed == 0 {
+        config.Speed = 1.0
+    }
+    if config.Volume < 0 {
+        config.Volume = 1.0
+    }
+    return config
 }
 
 // GetDefaultConfig mendapatkan konfigurasi default berdasarkan OS
@@ -481,10 +541,12 @@ func GetDefaultConfig() TTSConfig {
     }
 }
 
-// CreateTTSService membuat TTS service berdasarkan OS dan preferensi
-func CreateTTSService(useCloud bool, cloudAPIKey string) TTSService {
+// CreateTTSService membuat TTS service berdasarkan preferensi.
+// Service sistem memakai engine dari registry sesuai urutannya.
+func CreateTTSService(useCloud bool, cloudAPIKey string, engines *EngineRegistry) TTSService {
+    system := NewSystemTTSService(engines)
     if useCloud && cloudAPIKey != "" {
-        return NewCloudTTSService(cloudAPIKey)
+        return NewCloudTTSService(cloudAPIKey, "", system)
     }
-    return NewSystemTTSService()
+    return system
 }
\ No newline at end of file
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Jenis timing mark
+const (
+	MarkSentence = "sentence"
+	MarkWord     = "word"
+)
+
+// Mark penanda waktu kata atau kalimat di audio.
+// Start/End adalah offset rune di teks asli (End eksklusif).
+type Mark struct {
+	Type       string  `json:"type"`
+	Text       string  `json:"text"`
+	Start      int     `json:"start"`
+	End        int     `json:"end"`
+	TimeMs     float64 `json:"time_ms"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+const (
+	// Bobot jeda relatif terhadap satu huruf
+	commaPauseWeight    = 3
+	sentencePauseWeight = 6
+
+	// Jeda hening minimal yang dianggap batas kalimat di audio engine
+	minSilenceMs = 120
+	// Amplitudo PCM 16-bit di bawah ini dianggap hening (sekitar -36 dB)
+	silenceThreshold = 500
+)
+
+// silence rentang hening di audio, dalam milidetik
+type silence struct {
+	Start float64
+	End   float64
+}
+
+// span rentang waktu bicara untuk satu kalimat
+type span struct {
+	Start float64
+	End   float64
+}
+
+// marksForAudio membuat mark kalimat dan kata untuk satu potongan audio.
+// Batas kalimat diambil dari jeda hening di audio engine jika ada,
+// sedangkan waktu tiap kata diperkirakan dari panjangnya.
+func marksForAudio(chunk Segment, audio []byte, offsetMs float64) []Mark {
+	durationMs := wavDurationMs(audio)
+	if durationMs <= 0 {
+		return nil
+	}
+
+	sentences := SplitSentences(chunk.Text)
+	for i := range sentences {
+		sentences[i].Start += chunk.Start
+		sentences[i].End += chunk.Start
+	}
+
+	weights := make([]float64, len(sentences))
+	for i, sentence := range sentences {
+		weights[i] = textWeight(sentence.Text)
+	}
+
+	spans := alignSentences(weights, durationMs, findSilences(audio))
+
+	marks := []Mark{}
+	for i, sentence := range sentences {
+		marks = append(marks, Mark{
+			Type:       MarkSentence,
+			Text:       sentence.Text,
+			Start:      sentence.Start,
+			End:        sentence.End,
+			TimeMs:     offsetMs + spans[i].Start,
+			DurationMs: spans[i].End - spans[i].Start,
+		})
+		marks = append(marks, estimateWordMarks(sentence, offsetMs+spans[i].Start, spans[i].End-spans[i].Start)...)
+	}
+	return marks
+}
+
+// EstimateMarks memperkirakan mark untuk teks yang diucapkan selama durationMs,
+// dipakai jika audio dari engine tidak tersedia (mis. diputar langsung di host)
+func EstimateMarks(segment Segment, startMs, durationMs float64) []Mark {
+	marks := []Mark{{
+		Type:       MarkSentence,
+		Text:       segment.Text,
+		Start:      segment.Start,
+		End:        segment.End,
+		TimeMs:     startMs,
+		DurationMs: durationMs,
+	}}
+	return append(marks, estimateWordMarks(segment, startMs, durationMs)...)
+}
+
+// estimateWordMarks membagi durasi kalimat ke tiap kata sesuai bobotnya
+func estimateWordMarks(sentence Segment, startMs, durationMs float64) []Mark {
+	words := splitWords(sentence)
+	if len(words) == 0 {
+		return nil
+	}
+
+	total := 0.0
+	weights := make([]float64, len(words))
+	for i, word := range words {
+		weights[i] = wordWeight(word.Text)
+		total += weights[i]
+	}
+
+	marks := make([]Mark, 0, len(words))
+	elapsed := 0.0
+	for i, word := range words {
+		length := durationMs * weights[i] / total
+		marks = append(marks, Mark{
+			Type:       MarkWord,
+			Text:       word.Text,
+			Start:      word.Start,
+			End:        word.End,
+			TimeMs:     startMs + elapsed,
+			DurationMs: length,
+		})
+		elapsed += length
+	}
+	return marks
+}
+
+// splitWords memecah kalimat per spasi, offset tetap mengacu ke teks asli
+func splitWords(sentence Segment) []Segment {
+	runes := []rune(sentence.Text)
+	words := []Segment{}
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && !unicode.IsSpace(runes[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, Segment{
+				Text:  string(runes[start:i]),
+				Start: sentence.Start + start,
+				End:   sentence.Start + i,
+			})
+			start = -1
+		}
+	}
+	return words
+}
+
+// wordWeight perkiraan lama pengucapan satu kata, termasuk jeda tanda baca
+func wordWeight(word string) float64 {
+	weight := 1.0 // Transisi antar kata
+	for _, r := range word {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			weight++
+		}
+	}
+	if strings.TrimRight(word, ",;:") != word {
+		weight += commaPauseWeight
+	}
+	return weight
+}
+
+func textWeight(text string) float64 {
+	weight := sentencePauseWeight * 1.0
+	for _, word := range strings.Fields(text) {
+		weight += wordWeight(word)
+	}
+	return weight
+}
+
+// alignSentences membagi durasi audio ke tiap kalimat. Batas kalimat
+// ditempatkan di jeda hening yang paling dekat dengan perkiraan dari bobot.
+func alignSentences(weights []float64, durationMs float64, silences []silence) []span {
+	speechStart, speechEnd := 0.0, durationMs
+	interior := []silence{}
+	for _, gap := range silences {
+		switch {
+		case gap.Start <= 0:
+			speechStart = gap.End
+		case gap.End >= durationMs:
+			speechEnd = gap.Start
+		default:
+			interior = append(interior, gap)
+		}
+	}
+	if speechEnd <= speechStart {
+		speechStart, speechEnd = 0, durationMs
+	}
+
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	spans := make([]span, len(weights))
+	if len(spans) == 0 {
+		return spans
+	}
+	length := speechEnd - speechStart
+	window := math.Max(500, length*0.15)
+
+	cumulative := 0.0
+	start := speechStart
+	next := 0 // Jeda pertama yang belum dipakai
+	for i := 0; i < len(weights)-1; i++ {
+		cumulative += weights[i]
+		estimate := speechStart + length*cumulative/total
+
+		end, following := estimate, estimate
+		best := -1
+		for j := next; j < len(interior); j++ {
+			gap := interior[j]
+			if gap.Start < start {
+				continue
+			}
+			distance := math.Abs((gap.Start+gap.End)/2 - estimate)
+			if distance <= window && (best < 0 || distance < math.Abs((interior[best].Start+interior[best].End)/2-estimate)) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			end, following = interior[best].Start, interior[best].End
+			next = best + 1
+		}
+		if end < start {
+			end, following = start, start
+		}
+
+		spans[i] = span{Start: start, End: end}
+		start = following
+	}
+	spans[len(spans)-1] = span{Start: start, End: math.Max(start, speechEnd)}
+	return spans
+}
+
+// findSilences mencari jeda hening di WAV PCM 16-bit
+func findSilences(data []byte) []silence {
+	format, pcm, err := parseWAV(data)
+	if err != nil || format.AudioFormat != 1 || format.BitsPerSample != 16 || format.BlockAlign == 0 {
+		return nil
+	}
+
+	// Analisis per jendela 10ms
+	frameBytes := int(format.BlockAlign)
+	windowFrames := int(format.SampleRate) / 100
+	if windowFrames == 0 {
+		return nil
+	}
+	windowMs := float64(windowFrames) / float64(format.SampleRate) * 1000
+	frames := len(pcm) / frameBytes
+
+	silences := []silence{}
+	silentFrom := -1.0
+	for first := 0; first < frames; first += windowFrames {
+		last := first + windowFrames
+		if last > frames {
+			last = frames
+		}
+		peak := 0
+		for i := first * frameBytes; i+1 < last*frameBytes; i += 2 {
+			sample := int(int16(binary.LittleEndian.Uint16(pcm[i:])))
+			if sample < 0 {
+				sample = -sample
+			}
+			if sample > peak {
+				peak = sample
+			}
+		}
+
+		at := float64(first) / float64(windowFrames) * windowMs
+		if peak < silenceThreshold {
+			if silentFrom < 0 {
+				silentFrom = at
+			}
+			continue
+		}
+		if silentFrom >= 0 && at-silentFrom >= minSilenceMs {
+			silences = append(silences, silence{Start: silentFrom, End: at})
+		}
+		silentFrom = -1
+	}
+
+	end := float64(frames) / float64(format.SampleRate) * 1000
+	if silentFrom >= 0 && end-silentFrom >= minSilenceMs {
+		silences = append(silences, silence{Start: silentFrom, End: end})
+	}
+	return silences
+}
@@ -0,0 +1,396 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Verbalizer simbol: emoji, panah, bullet, URL, alamat email, tagar dan
+// mention diubah menjadi kata sebelum aturan angka, karena engine biasanya
+// melewatinya atau mengejanya karakter per karakter. Seberapa banyak yang
+// dibaca diatur config.verbosity.
+
+// Tingkat verbosity untuk config.verbosity; kosong sama dengan normal
+const (
+	VerbosityMinimal = "minimal" // Emoji dan hiasan dilewati, URL cukup dibaca "tautan"
+	VerbosityNormal  = "normal"  // Nama emoji (sekali per deret), domain URL, simbol umum
+	VerbosityVerbose = "verbose" // Jumlah emoji berulang, path URL, bullet dan tanda baca hiasan
+)
+
+const (
+	levelMinimal = iota
+	levelNormal
+	levelVerbose
+)
+
+// verbosityLevel mengubah config.verbosity menjadi tingkat angka
+func verbosityLevel(verbosity string) int {
+	switch verbosity {
+	case VerbosityMinimal:
+		return levelMinimal
+	case VerbosityVerbose:
+		return levelVerbose
+	}
+	return levelNormal
+}
+
+// symbolName bacaan satu simbol; level adalah verbosity terendah yang masih
+// membacanya (di bawahnya simbol dilewati)
+type symbolName struct {
+	text  string
+	level int
+}
+
+// verbalizerTable kata-kata verbalizer dalam satu bahasa
+type verbalizerTable struct {
+	reader  numberReader
+	emoji   map[string]string // Nama pendek CLDR; key tanpa variation selector dan warna kulit
+	flags   map[string]string // Kode negara ISO -> nama negara
+	symbols map[string]symbolName
+
+	flag    string // Format bendera, mis. "bendera %s"
+	repeat  string // Format emoji berulang: nama, jumlah
+	unknown string // Emoji yang tidak ada di tabel (hanya verbose)
+	link    string // Pengganti URL di verbosity minimal
+	at      string
+	hashtag string
+	dot     string
+	slash   string
+	dash    string
+	under   string // Garis bawah
+	plus    string
+}
+
+// verbalizer aturan yang sudah dikompilasi untuk setiap tingkat verbosity
+type verbalizer [levelVerbose + 1][]normalizeRule
+
+// newVerbalizer menyusun aturan verbalizer untuk semua tingkat verbosity
+func newVerbalizer(table verbalizerTable) verbalizer {
+	var v verbalizer
+	for level := range v {
+		v[level] = verbalizerRules(table, level)
+	}
+	return v
+}
+
+// verbalizerFor aturan verbalizer untuk bahasa dan verbosity config
+func verbalizerFor(config TTSConfig) []normalizeRule {
+	level := verbosityLevel(config.Verbosity)
+	if strings.HasPrefix(strings.ToLower(config.Language), "en") {
+		return englishVerbalizer[level]
+	}
+	return indonesianVerbalizer[level]
+}
+
+// emojiUnit satu emoji: bendera (dua regional indicator), keycap (1️⃣), atau
+// pictograph dengan modifier dan sambungan ZWJ (👨‍👩‍👧)
+const emojiUnit = `(?:[\x{1F1E6}-\x{1F1FF}]{2}|[0-9#*]\x{FE0F}?\x{20E3}|` +
+	`[\x{1F000}-\x{1FAFF}\x{2600}-\x{27BF}\x{2300}-\x{23FF}\x{2B00}-\x{2BFF}\x{203C}\x{2049}\x{3030}\x{303D}]` +
+	`[\x{FE0F}\x{1F3FB}-\x{1F3FF}]*(?:\x{200D}[\x{2600}-\x{27BF}\x{1F000}-\x{1FAFF}][\x{FE0F}\x{1F3FB}-\x{1F3FF}]*)*)`
+
+// verbalizerRules aturan verbalizer satu bahasa pada satu tingkat verbosity,
+// diurutkan supaya email menang atas mention dan tagar atas simbol "#"
+func verbalizerRules(t verbalizerTable, level int) []normalizeRule {
+	symbols := make([]string, 0, len(t.symbols))
+	for symbol := range t.symbols {
+		symbols = append(symbols, regexp.QuoteMeta(symbol))
+	}
+	sort.Slice(symbols, func(i, j int) bool { return len(symbols[i]) > len(symbols[j]) })
+
+	return []normalizeRule{
+		// Email: budi.santoso88@gmail.com
+		{
+			pattern: regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}._%+-]*@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)*\.\p{L}{2,}`),
+			expand: func(g []string) (string, bool) {
+				local, domain, _ := strings.Cut(g[0], "@")
+				return t.spellOut(local, t.reader.readDigits, true) + " " + t.at + " " + t.readDomain(domain), true
+			},
+		},
+		// URL: https://www.tokopedia.com/promo, www.kompas.id, detik.com
+		{
+			pattern: regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s<>"']*[^\s<>"'.,;:!?)\]]` +
+				`|[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)*\.(?:com|net|org|id|co|go|ac|sch|or|web|my|io|ly|gov|edu|info|biz|app|dev|me|tv|news)\b(?:/[^\s<>"']*[^\s<>"'.,;:!?)\]])?`),
+			expand: func(g []string) (string, bool) {
+				return t.readURL(g[0], level), true
+			},
+		},
+		// Mention: @budi_santoso
+		{
+			pattern: regexp.MustCompile(`@[\p{L}\p{N}_](?:[\p{L}\p{N}_.]*[\p{L}\p{N}_])?`),
+			expand: func(g []string) (string, bool) {
+				name := t.readIdentifier(g[0][1:])
+				if level == levelMinimal {
+					return name, true
+				}
+				return t.at + " " + name, true
+			},
+		},
+		// Tagar: #DiRumahAja -> "tagar Di Rumah Aja"
+		{
+			pattern: regexp.MustCompile(`#[\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*`),
+			expand: func(g []string) (string, bool) {
+				words := t.readIdentifier(g[0][1:])
+				if level == levelMinimal {
+					return words, true
+				}
+				return t.hashtag + " " + words, true
+			},
+		},
+		// Deret emoji: 😂😂👍 -> "wajah dengan air mata bahagia, jempol ke atas"
+		{
+			pattern: regexp.MustCompile(emojiUnit + `(?:\s*` + emojiUnit + `)*`),
+			expand: func(g []string) (string, bool) {
+				return t.readEmoji(g[0], level), true
+			},
+		},
+		// Simbol lain: panah, bullet, tanda centang, tanda matematika
+		{
+			pattern: regexp.MustCompile(`(?:` + strings.Join(symbols, "|") + `)\x{FE0F}?`),
+			expand: func(g []string) (string, bool) {
+				symbol := t.symbols[strings.TrimSuffix(g[0], "️")]
+				if symbol.level > level {
+					return "", true
+				}
+				return symbol.text, true
+			},
+		},
+	}
+}
+
+// readEmoji membaca deret emoji. Emoji yang sama berturut-turut dibaca
+// sekali; di verbose jumlahnya ikut dibaca.
+func (t verbalizerTable) readEmoji(run string, level int) string {
+	names := []string{}
+	counts := []int{}
+	last := ""
+	for _, sequence := range emojiSequences(run) {
+		if sequence == last {
+			counts[len(counts)-1]++
+			continue
+		}
+		last = sequence
+		names = append(names, t.emojiName(sequence, level))
+		counts = append(counts, 1)
+	}
+
+	parts := []string{}
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+		if level == levelVerbose && counts[i] > 1 {
+			name = fmt.Sprintf(t.repeat, name, t.reader.words(int64(counts[i])))
+		}
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// emojiName nama satu emoji, atau kosong jika dilewati pada level ini
+func (t verbalizerTable) emojiName(sequence string, level int) string {
+	key := strings.Map(func(r rune) rune {
+		if r == '️' || (r >= 0x1F3FB && r <= 0x1F3FF) {
+			return -1
+		}
+		return r
+	}, sequence)
+
+	if symbol, ok := t.symbols[key]; ok {
+		if symbol.level > level {
+			return ""
+		}
+		return symbol.text
+	}
+	if level == levelMinimal {
+		return ""
+	}
+	if name, ok := t.emoji[key]; ok {
+		return name
+	}
+
+	runes := []rune(key)
+	switch {
+	case len(runes) == 2 && runes[0] >= 0x1F1E6 && runes[0] <= 0x1F1FF:
+		code := string([]rune{'A' + runes[0] - 0x1F1E6, 'A' + runes[1] - 0x1F1E6})
+		country, ok := t.flags[code]
+		if !ok {
+			country = t.reader.spellCode(code)
+		}
+		return fmt.Sprintf(t.flag, country)
+	case len(runes) == 2 && runes[1] == 0x20E3:
+		if runes[0] >= '0' && runes[0] <= '9' {
+			return t.reader.digits[runes[0]-'0']
+		}
+		return t.symbols[string(runes[0])].text
+	}
+	// Sekuens ZWJ yang tidak dikenal dibaca dari komponen pertamanya
+	if first, _, found := strings.Cut(key, "‍"); found {
+		if name, ok := t.emoji[first]; ok {
+			return name
+		}
+	}
+	if level == levelVerbose {
+		return t.unknown
+	}
+	return ""
+}
+
+// emojiSequences memecah deret emoji menjadi emoji tunggal beserta modifiernya
+func emojiSequences(run string) []string {
+	sequences := []string{}
+	var current []rune
+	joining := false
+	for _, r := range run {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r == '‍':
+			joining = true
+			current = append(current, r)
+			continue
+		case r == '️' || r == '⃣' || (r >= 0x1F3FB && r <= 0x1F3FF):
+			current = append(current, r)
+			continue
+		case r >= 0x1F1E6 && r <= 0x1F1FF && len(current) == 1 && current[0] >= 0x1F1E6 && current[0] <= 0x1F1FF:
+			// Regional indicator kedua melengkapi bendera
+			current = append(current, r)
+			continue
+		case joining:
+			joining = false
+			current = append(current, r)
+			continue
+		}
+		if len(current) > 0 {
+			sequences = append(sequences, string(current))
+		}
+		current = []rune{r}
+	}
+	if len(current) > 0 {
+		sequences = append(sequences, string(current))
+	}
+	return sequences
+}
+
+// readURL membaca URL: minimal cukup "tautan", normal domainnya, verbose
+// domain beserta path (query dan fragment tidak dibaca)
+func (t verbalizerTable) readURL(address string, level int) string {
+	if level == levelMinimal {
+		return t.link
+	}
+
+	rest := address
+	if i := strings.Index(rest, "://"); i >= 0 {
+		rest = rest[i+3:]
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	if i := strings.IndexAny(host, "?#"); i >= 0 {
+		host = host[:i]
+	}
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	host, _, _ = strings.Cut(host, ":")
+	if len(host) > 4 && strings.EqualFold(host[:4], "www.") {
+		host = host[4:]
+	}
+
+	spoken := t.readDomain(host)
+	if level < levelVerbose {
+		return spoken
+	}
+
+	path, _, _ = strings.Cut(path, "?")
+	path, _, _ = strings.Cut(path, "#")
+	for _, segment := range strings.Split(path, "/") {
+		if decoded, err := url.PathUnescape(segment); err == nil {
+			segment = decoded
+		}
+		// Segmen kosong atau ID panjang (hash, token) tidak dibaca
+		if segment == "" || len(segment) > 30 {
+			continue
+		}
+		spoken += " " + t.slash + " " + t.spellOut(segment, t.reader.read, false)
+	}
+	return spoken
+}
+
+// readDomain membaca domain per label: "kompas.co.id" -> "kompas titik co titik id"
+func (t verbalizerTable) readDomain(domain string) string {
+	labels := strings.Split(strings.ToLower(domain), ".")
+	for i, label := range labels {
+		labels[i] = t.spellOut(label, t.reader.read, true)
+	}
+	return strings.Join(labels, " "+t.dot+" ")
+}
+
+// spellOut membaca potongan alamat: huruf apa adanya dan angka dengan
+// digits. Jika named diisi, pemisah (. _ - +) dibaca dengan namanya;
+// jika tidak, pemisah menjadi spasi (slug "promo-hari-ini").
+func (t verbalizerTable) spellOut(part string, digits func(string) string, named bool) string {
+	separators := map[rune]string{'.': t.dot, '_': t.under, '-': t.dash, '+': t.plus}
+	words := []string{}
+	var run []rune
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if run[0] >= '0' && run[0] <= '9' {
+			words = append(words, digits(string(run)))
+		} else {
+			words = append(words, string(run))
+		}
+		run = nil
+	}
+	for _, r := range part {
+		isDigit := r >= '0' && r <= '9'
+		switch {
+		case isWordRune(r):
+			if len(run) > 0 && (run[0] >= '0' && run[0] <= '9') != isDigit {
+				flush()
+			}
+			run = append(run, r)
+		default:
+			flush()
+			if name, ok := separators[r]; ok && named {
+				words = append(words, name)
+			}
+		}
+	}
+	flush()
+	return strings.Join(words, " ")
+}
+
+// readIdentifier membaca nama tagar/mention per kata, dengan angka dibaca
+// sebagai bilangan ("Covid19" -> "Covid sembilan belas")
+func (t verbalizerTable) readIdentifier(name string) string {
+	words := strings.Fields(splitIdentifier(name))
+	for i, word := range words {
+		if unicode.IsDigit([]rune(word)[0]) {
+			words[i] = t.reader.read(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// splitIdentifier memecah nama tagar/mention menjadi kata: "DiRumahAja" ->
+// "Di Rumah Aja", "budi_santoso" -> "budi santoso"
+func splitIdentifier(name string) string {
+	var result strings.Builder
+	var previous rune
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '.':
+			r = ' '
+		case previous != 0 && previous != ' ' && (unicode.IsUpper(r) && unicode.IsLower(previous) ||
+			unicode.IsDigit(r) != unicode.IsDigit(previous)):
+			result.WriteRune(' ')
+		}
+		result.WriteRune(r)
+		previous = r
+	}
+	return strings.Join(strings.Fields(result.String()), " ")
+}
@@ -0,0 +1,112 @@
+package services
+
+// englishVerbalizer aturan verbalizer bahasa Inggris per tingkat verbosity
+var englishVerbalizer = newVerbalizer(verbalizerTable{
+	reader:  englishReader,
+	emoji:   englishEmoji,
+	flags:   englishCountries,
+	symbols: englishSymbols,
+	flag:    "flag of %s",
+	repeat:  "%s %s times",
+	unknown: "emoji",
+	link:    "link",
+	at:      "at",
+	hashtag: "hashtag",
+	dot:     "dot",
+	slash:   "slash",
+	dash:    "dash",
+	under:   "underscore",
+	plus:    "plus",
+})
+
+// englishSymbols simbol yang bukan emoji biasa, dengan level verbosity terendah
+var englishSymbols = map[string]symbolName{
+	"✅": {"check mark", levelMinimal}, "✔": {"check mark", levelMinimal}, "✓": {"check mark", levelMinimal},
+	"☑": {"check box with check", levelMinimal}, "❌": {"cross mark", levelMinimal}, "❎": {"cross mark", levelMinimal},
+	"✖": {"cross mark", levelMinimal}, "✗": {"cross mark", levelMinimal}, "✘": {"cross mark", levelMinimal},
+	"&": {"and", levelMinimal},
+
+	"→": {"right arrow", levelNormal}, "←": {"left arrow", levelNormal}, "↑": {"up arrow", levelNormal},
+	"↓": {"down arrow", levelNormal}, "↔": {"left-right arrow", levelNormal}, "⇒": {"double right arrow", levelNormal},
+	"⇐": {"double left arrow", levelNormal}, "⇔": {"double left-right arrow", levelNormal},
+	"➡": {"right arrow", levelNormal}, "⬅": {"left arrow", levelNormal}, "⬆": {"up arrow", levelNormal},
+	"⬇": {"down arrow", levelNormal},
+	"©": {"copyright", levelNormal}, "®": {"registered", levelNormal}, "™": {"trade mark", levelNormal},
+	"×": {"times", levelNormal}, "÷": {"divided by", levelNormal}, "±": {"plus or minus", levelNormal},
+	"=": {"equals", levelNormal}, "≠": {"not equal to", levelNormal}, "≈": {"approximately", levelNormal},
+	"≤": {"less than or equal to", levelNormal}, "≥": {"greater than or equal to", levelNormal},
+	"~": {"about", levelNormal}, "§": {"section", levelNormal}, "#": {"number", levelNormal},
+	"@": {"at", levelNormal}, "*": {"asterisk", levelVerbose},
+
+	"•": {"bullet", levelVerbose}, "◦": {"bullet", levelVerbose}, "‣": {"bullet", levelVerbose},
+	"∙": {"bullet", levelVerbose}, "▪": {"bullet", levelVerbose}, "▫": {"bullet", levelVerbose},
+	"●": {"bullet", levelVerbose}, "○": {"bullet", levelVerbose}, "■": {"bullet", levelVerbose},
+	"□": {"bullet", levelVerbose}, "►": {"bullet", levelVerbose}, "▶": {"bullet", levelVerbose},
+	"➤": {"bullet", levelVerbose}, "❖": {"bullet", levelVerbose}, "✦": {"star", levelVerbose},
+	"★": {"star", levelVerbose}, "☆": {"star", levelVerbose}, "|": {"vertical bar", levelVerbose},
+	"¶": {"pilcrow", levelVerbose},
+}
+
+// englishEmoji nama pendek CLDR (en) untuk emoji yang sering muncul di chat dan berita
+var englishEmoji = map[string]string{
+	"😀": "grinning face", "😃": "grinning face with big eyes", "😄": "grinning face with smiling eyes",
+	"😁": "beaming face with smiling eyes", "😆": "grinning squinting face", "😅": "grinning face with sweat",
+	"🤣": "rolling on the floor laughing", "😂": "face with tears of joy", "🙂": "slightly smiling face",
+	"🙃": "upside-down face", "😉": "winking face", "😊": "smiling face with smiling eyes",
+	"😇": "smiling face with halo", "🥰": "smiling face with hearts", "😍": "smiling face with heart-eyes",
+	"🤩": "star-struck", "😘": "face blowing a kiss", "😋": "face savoring food", "😛": "face with tongue",
+	"😜": "winking face with tongue", "🤪": "zany face", "🤗": "smiling face with open hands",
+	"🤭": "face with hand over mouth", "🤫": "shushing face", "🤔": "thinking face", "😐": "neutral face",
+	"😑": "expressionless face", "😶": "face without mouth", "😏": "smirking face", "😒": "unamused face",
+	"🙄": "face with rolling eyes", "😬": "grimacing face", "😌": "relieved face", "😔": "pensive face",
+	"😪": "sleepy face", "😴": "sleeping face", "😷": "face with medical mask", "🤒": "face with thermometer",
+	"🤕": "face with head-bandage", "🤢": "nauseated face", "🤧": "sneezing face", "🥵": "hot face",
+	"🥶": "cold face", "😵": "face with crossed-out eyes", "🤯": "exploding head", "🥳": "partying face",
+	"😎": "smiling face with sunglasses", "😕": "confused face", "😟": "worried face",
+	"🙁": "slightly frowning face", "☹": "frowning face", "😮": "face with open mouth", "😲": "astonished face",
+	"😳": "flushed face", "🥺": "pleading face", "😨": "fearful face", "😰": "anxious face with sweat",
+	"😥": "sad but relieved face", "😢": "crying face", "😭": "loudly crying face", "😱": "face screaming in fear",
+	"😞": "disappointed face", "😓": "downcast face with sweat", "😩": "weary face", "😫": "tired face",
+	"🥱": "yawning face", "😤": "face with steam from nose", "😡": "enraged face", "😠": "angry face",
+	"🤬": "face with symbols on mouth", "💀": "skull", "🤡": "clown face", "👻": "ghost", "🤖": "robot",
+	"🙈": "see-no-evil monkey", "🙉": "hear-no-evil monkey", "🙊": "speak-no-evil monkey", "❤": "red heart",
+	"🧡": "orange heart", "💛": "yellow heart", "💚": "green heart", "💙": "blue heart", "💜": "purple heart",
+	"🖤": "black heart", "🤍": "white heart", "🤎": "brown heart", "💔": "broken heart", "💕": "two hearts",
+	"💞": "revolving hearts", "💓": "beating heart", "💖": "sparkling heart", "💘": "heart with arrow",
+	"💝": "heart with ribbon", "❤‍🔥": "heart on fire", "💯": "hundred points", "💥": "collision",
+	"💦": "sweat droplets", "💤": "zzz", "👋": "waving hand", "✋": "raised hand", "👌": "OK hand",
+	"✌": "victory hand", "🤞": "crossed fingers", "🤟": "love-you gesture", "🤙": "call me hand",
+	"👈": "backhand index pointing left", "👉": "backhand index pointing right",
+	"👆": "backhand index pointing up", "👇": "backhand index pointing down", "☝": "index pointing up",
+	"👍": "thumbs up", "👎": "thumbs down", "✊": "raised fist", "👊": "oncoming fist", "👏": "clapping hands",
+	"🙌": "raising hands", "🤲": "palms up together", "🤝": "handshake", "🙏": "folded hands", "💪": "flexed biceps",
+	"👀": "eyes", "👶": "baby", "👦": "boy", "👧": "girl", "👨": "man", "👩": "woman", "👴": "old man",
+	"👵": "old woman", "👪": "family", "👨‍👩‍👧‍👦": "family: man, woman, girl, boy", "🧕": "woman with headscarf",
+	"👮": "police officer", "🧑‍⚕": "health worker", "🕌": "mosque", "🕋": "kaaba", "⛪": "church",
+	"🌙": "crescent moon", "⭐": "star", "🌟": "glowing star", "✨": "sparkles", "☀": "sun",
+	"⛅": "sun behind cloud", "🌧": "cloud with rain", "⛈": "cloud with lightning and rain",
+	"☔": "umbrella with rain drops", "🌈": "rainbow", "🔥": "fire", "💧": "droplet", "🌹": "rose",
+	"🌸": "cherry blossom", "🌺": "hibiscus", "🌻": "sunflower", "🍀": "four leaf clover", "🐱": "cat face",
+	"🐶": "dog face", "🐔": "chicken", "🐟": "fish", "🍚": "cooked rice", "🍜": "steaming bowl", "☕": "hot beverage",
+	"🍵": "teacup without handle", "🎂": "birthday cake", "🍰": "shortcake", "🎉": "party popper",
+	"🎊": "confetti ball", "🎁": "wrapped gift", "🎈": "balloon", "🏆": "trophy", "🥇": "1st place medal",
+	"⚽": "soccer ball", "👑": "crown", "🏠": "house", "🏥": "hospital", "🚗": "automobile", "🏍": "motorcycle",
+	"✈": "airplane", "📱": "mobile phone", "☎": "telephone", "📞": "telephone receiver", "💻": "laptop",
+	"📷": "camera", "📺": "television", "📢": "loudspeaker", "📣": "megaphone", "🔔": "bell", "📌": "pushpin",
+	"📍": "round pushpin", "📅": "calendar", "⏰": "alarm clock", "⌛": "hourglass done", "⏳": "hourglass not done",
+	"💰": "money bag", "💵": "dollar banknote", "💸": "money with wings", "💳": "credit card", "🛒": "shopping cart",
+	"📦": "package", "✉": "envelope", "📧": "e-mail", "📝": "memo", "📖": "open book", "🔒": "locked", "🔑": "key",
+	"⚠": "warning", "🚫": "prohibited", "⛔": "no entry", "❗": "red exclamation mark", "❓": "red question mark",
+	"‼": "double exclamation mark", "⁉": "exclamation question mark", "🆗": "OK button", "🆕": "NEW button",
+	"🆓": "FREE button", "🔴": "red circle", "🟢": "green circle", "🔵": "blue circle", "⚫": "black circle",
+	"⚪": "white circle",
+}
+
+// englishCountries nama negara untuk emoji bendera
+var englishCountries = map[string]string{
+	"ID": "Indonesia", "MY": "Malaysia", "SG": "Singapore", "BN": "Brunei", "TH": "Thailand",
+	"PH": "Philippines", "VN": "Vietnam", "TL": "Timor-Leste", "AU": "Australia", "JP": "Japan",
+	"KR": "South Korea", "CN": "China", "IN": "India", "SA": "Saudi Arabia", "PS": "Palestinian Territories",
+	"TR": "Turkey", "EG": "Egypt", "NL": "Netherlands", "GB": "United Kingdom", "DE": "Germany", "FR": "France",
+	"IT": "Italy", "ES": "Spain", "US": "United States", "BR": "Brazil", "AR": "Argentina",
+}
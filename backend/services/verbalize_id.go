@@ -0,0 +1,119 @@
+package services
+
+// indonesianVerbalizer aturan verbalizer bahasa Indonesia per tingkat verbosity
+var indonesianVerbalizer = newVerbalizer(verbalizerTable{
+	reader:  indonesianReader,
+	emoji:   indonesianEmoji,
+	flags:   indonesianCountries,
+	symbols: indonesianSymbols,
+	flag:    "bendera %s",
+	repeat:  "%s %s kali",
+	unknown: "emoji",
+	link:    "tautan",
+	at:      "at",
+	hashtag: "tagar",
+	dot:     "titik",
+	slash:   "garis miring",
+	dash:    "strip",
+	under:   "garis bawah",
+	plus:    "plus",
+})
+
+// indonesianSymbols simbol yang bukan emoji biasa, dengan level verbosity terendah
+var indonesianSymbols = map[string]symbolName{
+	// Tanda centang dan silang tetap dibaca di minimal karena membawa arti
+	"✅": {"tanda centang", levelMinimal}, "✔": {"tanda centang", levelMinimal}, "✓": {"tanda centang", levelMinimal},
+	"☑": {"kotak centang", levelMinimal}, "❌": {"tanda silang", levelMinimal}, "❎": {"tanda silang", levelMinimal},
+	"✖": {"tanda silang", levelMinimal}, "✗": {"tanda silang", levelMinimal}, "✘": {"tanda silang", levelMinimal},
+	"&": {"dan", levelMinimal},
+
+	"→": {"panah kanan", levelNormal}, "←": {"panah kiri", levelNormal}, "↑": {"panah atas", levelNormal},
+	"↓": {"panah bawah", levelNormal}, "↔": {"panah kiri kanan", levelNormal}, "⇒": {"panah ganda kanan", levelNormal},
+	"⇐": {"panah ganda kiri", levelNormal}, "⇔": {"panah ganda kiri kanan", levelNormal},
+	"➡": {"panah kanan", levelNormal}, "⬅": {"panah kiri", levelNormal}, "⬆": {"panah atas", levelNormal},
+	"⬇": {"panah bawah", levelNormal},
+	"©": {"hak cipta", levelNormal}, "®": {"merek terdaftar", levelNormal}, "™": {"merek dagang", levelNormal},
+	"×": {"kali", levelNormal}, "÷": {"bagi", levelNormal}, "±": {"plus minus", levelNormal},
+	"=": {"sama dengan", levelNormal}, "≠": {"tidak sama dengan", levelNormal}, "≈": {"kira-kira", levelNormal},
+	"≤": {"kurang dari atau sama dengan", levelNormal}, "≥": {"lebih dari atau sama dengan", levelNormal},
+	"~": {"sekitar", levelNormal}, "§": {"pasal", levelNormal}, "#": {"nomor", levelNormal},
+	"@": {"at", levelNormal}, "*": {"bintang", levelVerbose},
+
+	// Bullet dan hiasan hanya dibaca di verbose
+	"•": {"butir", levelVerbose}, "◦": {"butir", levelVerbose}, "‣": {"butir", levelVerbose},
+	"∙": {"butir", levelVerbose}, "▪": {"butir", levelVerbose}, "▫": {"butir", levelVerbose},
+	"●": {"butir", levelVerbose}, "○": {"butir", levelVerbose}, "■": {"butir", levelVerbose},
+	"□": {"butir", levelVerbose}, "►": {"butir", levelVerbose}, "▶": {"butir", levelVerbose},
+	"➤": {"butir", levelVerbose}, "❖": {"butir", levelVerbose}, "✦": {"bintang", levelVerbose},
+	"★": {"bintang", levelVerbose}, "☆": {"bintang", levelVerbose}, "|": {"garis tegak", levelVerbose},
+	"¶": {"paragraf", levelVerbose},
+}
+
+// indonesianEmoji nama pendek CLDR (id) untuk emoji yang sering muncul di chat dan berita
+var indonesianEmoji = map[string]string{
+	"😀": "wajah gembira", "😃": "wajah gembira dengan mata besar", "😄": "wajah gembira dengan mata tersenyum",
+	"😁": "wajah berseri dengan mata tersenyum", "😆": "wajah gembira dengan mata terpejam",
+	"😅": "wajah gembira berkeringat", "🤣": "berguling-guling sambil tertawa",
+	"😂": "wajah dengan air mata bahagia", "🙂": "wajah sedikit tersenyum", "🙃": "wajah terbalik",
+	"😉": "wajah mengedipkan mata", "😊": "wajah tersenyum dengan mata tersenyum",
+	"😇": "wajah tersenyum dengan lingkaran cahaya", "🥰": "wajah tersenyum dengan hati",
+	"😍": "wajah tersenyum dengan mata hati", "🤩": "wajah kagum", "😘": "wajah memberi ciuman",
+	"😋": "wajah menikmati makanan", "😛": "wajah menjulurkan lidah",
+	"😜": "wajah mengedip sambil menjulurkan lidah", "🤪": "wajah konyol",
+	"🤗": "wajah tersenyum dengan tangan terbuka", "🤭": "wajah menutup mulut dengan tangan",
+	"🤫": "wajah menyuruh diam", "🤔": "wajah berpikir", "😐": "wajah datar", "😑": "wajah tanpa ekspresi",
+	"😶": "wajah tanpa mulut", "😏": "wajah menyeringai", "😒": "wajah tidak senang", "🙄": "wajah memutar mata",
+	"😬": "wajah meringis", "😌": "wajah lega", "😔": "wajah murung", "😪": "wajah mengantuk", "😴": "wajah tidur",
+	"😷": "wajah memakai masker", "🤒": "wajah dengan termometer", "🤕": "wajah dengan perban di kepala",
+	"🤢": "wajah mual", "🤧": "wajah bersin", "🥵": "wajah kepanasan", "🥶": "wajah kedinginan",
+	"😵": "wajah pusing", "🤯": "kepala meledak", "🥳": "wajah berpesta",
+	"😎": "wajah tersenyum dengan kacamata hitam", "😕": "wajah bingung", "😟": "wajah khawatir",
+	"🙁": "wajah sedikit cemberut", "☹": "wajah cemberut", "😮": "wajah dengan mulut terbuka",
+	"😲": "wajah tercengang", "😳": "wajah tersipu", "🥺": "wajah memohon", "😨": "wajah takut",
+	"😰": "wajah cemas berkeringat", "😥": "wajah sedih tapi lega", "😢": "wajah menangis",
+	"😭": "wajah menangis keras", "😱": "wajah menjerit ketakutan", "😞": "wajah kecewa",
+	"😓": "wajah lesu berkeringat", "😩": "wajah letih", "😫": "wajah lelah", "🥱": "wajah menguap",
+	"😤": "wajah mendengus", "😡": "wajah sangat marah", "😠": "wajah marah", "🤬": "wajah mengumpat",
+	"💀": "tengkorak", "🤡": "wajah badut", "👻": "hantu", "🤖": "robot", "🙈": "monyet menutup mata",
+	"🙉": "monyet menutup telinga", "🙊": "monyet menutup mulut", "❤": "hati merah", "🧡": "hati oranye",
+	"💛": "hati kuning", "💚": "hati hijau", "💙": "hati biru", "💜": "hati ungu", "🖤": "hati hitam",
+	"🤍": "hati putih", "🤎": "hati cokelat", "💔": "hati patah", "💕": "dua hati", "💞": "hati berputar",
+	"💓": "hati berdebar", "💖": "hati berkilau", "💘": "hati dengan panah", "💝": "hati berpita",
+	"❤‍🔥": "hati membara", "💯": "seratus poin", "💥": "tabrakan", "💦": "tetesan keringat", "💤": "zzz",
+	"👋": "tangan melambai", "✋": "tangan terangkat", "👌": "tangan oke", "✌": "tangan tanda kemenangan",
+	"🤞": "jari bersilang", "🤟": "isyarat aku sayang kamu", "🤙": "tangan telepon aku",
+	"👈": "telunjuk menunjuk ke kiri", "👉": "telunjuk menunjuk ke kanan", "👆": "telunjuk menunjuk ke atas",
+	"👇": "telunjuk menunjuk ke bawah", "☝": "telunjuk ke atas", "👍": "jempol ke atas", "👎": "jempol ke bawah",
+	"✊": "kepalan tangan terangkat", "👊": "tinju", "👏": "tepuk tangan", "🙌": "mengangkat kedua tangan",
+	"🤲": "kedua telapak tangan menengadah", "🤝": "jabat tangan", "🙏": "tangan terkatup", "💪": "otot lengan",
+	"👀": "mata", "👶": "bayi", "👦": "anak laki-laki", "👧": "anak perempuan", "👨": "pria", "👩": "wanita",
+	"👴": "kakek", "👵": "nenek", "👪": "keluarga",
+	"👨‍👩‍👧‍👦": "keluarga: pria, wanita, anak perempuan, anak laki-laki", "🧕": "wanita berkerudung",
+	"👮": "polisi", "🧑‍⚕": "tenaga kesehatan", "🕌": "masjid", "🕋": "Kakbah", "⛪": "gereja", "🌙": "bulan sabit",
+	"⭐": "bintang", "🌟": "bintang bersinar", "✨": "kilauan", "☀": "matahari", "⛅": "matahari di balik awan",
+	"🌧": "awan hujan", "⛈": "awan petir dan hujan", "☔": "payung dengan tetesan hujan", "🌈": "pelangi",
+	"🔥": "api", "💧": "tetesan air", "🌹": "mawar", "🌸": "bunga sakura", "🌺": "kembang sepatu",
+	"🌻": "bunga matahari", "🍀": "semanggi berdaun empat", "🐱": "wajah kucing", "🐶": "wajah anjing", "🐔": "ayam",
+	"🐟": "ikan", "🍚": "nasi", "🍜": "mangkuk mi panas", "☕": "minuman panas", "🍵": "cangkir teh",
+	"🎂": "kue ulang tahun", "🍰": "kue", "🎉": "terompet pesta", "🎊": "bola konfeti", "🎁": "kado", "🎈": "balon",
+	"🏆": "trofi", "🥇": "medali juara satu", "⚽": "bola sepak", "👑": "mahkota", "🏠": "rumah", "🏥": "rumah sakit",
+	"🚗": "mobil", "🏍": "sepeda motor", "✈": "pesawat", "📱": "ponsel", "☎": "telepon", "📞": "gagang telepon",
+	"💻": "laptop", "📷": "kamera", "📺": "televisi", "📢": "pengeras suara", "📣": "megafon", "🔔": "lonceng",
+	"📌": "paku payung", "📍": "pin bulat", "📅": "kalender", "⏰": "jam weker", "⌛": "jam pasir",
+	"⏳": "jam pasir berjalan", "💰": "kantong uang", "💵": "uang kertas dolar", "💸": "uang bersayap",
+	"💳": "kartu kredit", "🛒": "troli belanja", "📦": "paket", "✉": "amplop", "📧": "email", "📝": "memo",
+	"📖": "buku terbuka", "🔒": "terkunci", "🔑": "kunci", "⚠": "peringatan", "🚫": "dilarang",
+	"⛔": "dilarang masuk", "❗": "tanda seru merah", "❓": "tanda tanya merah", "‼": "tanda seru ganda",
+	"⁉": "tanda seru dan tanda tanya", "🆗": "tombol OK", "🆕": "tombol baru", "🆓": "tombol gratis",
+	"🔴": "lingkaran merah", "🟢": "lingkaran hijau", "🔵": "lingkaran biru", "⚫": "lingkaran hitam",
+	"⚪": "lingkaran putih",
+}
+
+// indonesianCountries nama negara untuk emoji bendera
+var indonesianCountries = map[string]string{
+	"ID": "Indonesia", "MY": "Malaysia", "SG": "Singapura", "BN": "Brunei", "TH": "Thailand", "PH": "Filipina",
+	"VN": "Vietnam", "TL": "Timor Leste", "AU": "Australia", "JP": "Jepang", "KR": "Korea Selatan",
+	"CN": "Tiongkok", "IN": "India", "SA": "Arab Saudi", "PS": "Palestina", "TR": "Turki", "EG": "Mesir",
+	"NL": "Belanda", "GB": "Inggris Raya", "DE": "Jerman", "FR": "Prancis", "IT": "Italia", "ES": "Spanyol",
+	"US": "Amerika Serikat", "BR": "Brasil", "AR": "Argentina",
+}
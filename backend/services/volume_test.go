@@ -0,0 +1,50 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyConfigDefaultsVolume(t *testing.T) {
+	tests := []struct {
+		volume float64
+		want   float64
+	}{
+		{VolumeDefault, 1.0},
+		{0, 0},
+		{0.5, 0.5},
+	}
+	for _, tt := range tests {
+		if got := applyConfigDefaults(TTSConfig{Volume: tt.volume}).Volume; got != tt.want {
+			t.Errorf("applyConfigDefaults(volume %v) = %v, want %v", tt.volume, got, tt.want)
+		}
+	}
+}
+
+// Volume 0 harus sampai ke engine sebagai bisu, bukan diabaikan
+func TestMutedVolumeReachesEngines(t *testing.T) {
+	config := TTSConfig{Language: "id-ID", Speed: 1.0, Volume: 0}
+
+	args := sayArgs("halo", config, "")
+	if text := args[len(args)-1]; text != "[[volm 0.00]] halo" {
+		t.Errorf("say text = %q, want muted", text)
+	}
+
+	script, err := festivalSetup(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(script, "utt.wave.rescale utt 0.00") {
+		t.Errorf("festival script does not mute:\n%s", script)
+	}
+
+	_, pcm, err := parseWAV(synthesizeFormant("halo", 1.0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range pcm {
+		if b != 0 {
+			t.Fatalf("builtin engine sample byte %d = %d, want silence", i, b)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// wavFormat informasi format dari header WAV (chunk "fmt ")
+type wavFormat struct {
+	AudioFormat   uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+// parseWAV membaca header RIFF/WAVE dan mengembalikan format serta data PCM
+func parseWAV(data []byte) (wavFormat, []byte, error) {
+	var format wavFormat
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return format, nil, fmt.Errorf("not a WAV file")
+	}
+
+	hasFormat := false
+	pos := 12
+	for pos+8 <= len(data) {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := pos + 8
+
+		switch id {
+		case "fmt ":
+			if size < 16 || body+16 > len(data) {
+				return format, nil, fmt.Errorf("invalid fmt chunk")
+			}
+			format = wavFormat{
+				AudioFormat:   binary.LittleEndian.Uint16(data[body:]),
+				Channels:      binary.LittleEndian.Uint16(data[body+2:]),
+				SampleRate:    binary.LittleEndian.Uint32(data[body+4:]),
+				ByteRate:      binary.LittleEndian.Uint32(data[body+8:]),
+				BlockAlign:    binary.LittleEndian.Uint16(data[body+12:]),
+				BitsPerSample: binary.LittleEndian.Uint16(data[body+14:]),
+			}
+			hasFormat = true
+		case "data":
+			if !hasFormat {
+				return format, nil, fmt.Errorf("data chunk before fmt chunk")
+			}
+			// Engine yang menulis ke pipe (mis. espeak --stdout) tidak tahu
+			// ukuran akhirnya, jadi ukuran chunk bisa lebih besar dari isi file
+			end := body + size
+			if size < 0 || end > len(data) {
+				end = len(data)
+			}
+			return format, data[body:end], nil
+		}
+
+		// Chunk selalu di-pad ke jumlah byte genap
+		pos = body + size + size%2
+	}
+
+	return format, nil, fmt.Errorf("WAV data chunk not found")
+}
+
+// wavDurationMs menghitung panjang audio WAV dalam milidetik (0 jika tidak valid)
+func wavDurationMs(data []byte) float64 {
+	format, pcm, err := parseWAV(data)
+	if err != nil || format.ByteRate == 0 {
+		return 0
+	}
+	return float64(len(pcm)) / float64(format.ByteRate) * 1000
+}
+
+// encodeWAV membuat file WAV dari format dan data PCM
+func encodeWAV(format wavFormat, pcm []byte) []byte {
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(36+len(pcm)))
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], format.AudioFormat)
+	binary.LittleEndian.PutUint16(header[22:24], format.Channels)
+	binary.LittleEndian.PutUint32(header[24:28], format.SampleRate)
+	binary.LittleEndian.PutUint32(header[28:32], format.ByteRate)
+	binary.LittleEndian.PutUint16(header[32:34], format.BlockAlign)
+	binary.LittleEndian.PutUint16(header[34:36], format.BitsPerSample)
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(len(pcm)))
+	return append(header, pcm...)
+}
+
+// concatWAV menggabungkan beberapa file WAV berformat sama menjadi satu
+func concatWAV(parts [][]byte) ([]byte, error) {
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("no audio to concatenate")
+	}
+
+	var format wavFormat
+	var pcm []byte
+	for i, part := range parts {
+		partFormat, partPCM, err := parseWAV(part)
+		if err != nil {
+			return nil, fmt.Errorf("part %d: %v", i, err)
+		}
+		if i == 0 {
+			format = partFormat
+		} else if partFormat != format {
+			return nil, fmt.Errorf("part %d: audio format differs from part 0", i)
+		}
+		pcm = append(pcm, partPCM...)
+	}
+	return encodeWAV(format, pcm), nil
+}
+
+// concatMixedWAV sama dengan concatWAV, tapi potongan dengan format berbeda
+// (mis. voice bahasa lain dari engine lain) diubah dulu ke PCM 16-bit mono
+// dengan sample rate potongan pertama
+func concatMixedWAV(parts [][]byte) ([]byte, error) {
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("no audio to concatenate")
+	}
+	first, _, err := parseWAV(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("part 0: %v", err)
+	}
+	mixed := false
+	for _, part := range parts[1:] {
+		if format, _, err := parseWAV(part); err == nil && format != first {
+			mixed = true
+		}
+	}
+	if !mixed {
+		return concatWAV(parts)
+	}
+
+	mono := wavFormat{
+		AudioFormat:   1,
+		Channels:      1,
+		SampleRate:    first.SampleRate,
+		ByteRate:      first.SampleRate * 2,
+		BlockAlign:    2,
+		BitsPerSample: 16,
+	}
+	converted := make([][]byte, len(parts))
+	for i, part := range parts {
+		if converted[i], err = convertWAV(part, mono); err != nil {
+			return nil, fmt.Errorf("part %d: %v", i, err)
+		}
+	}
+	return concatWAV(converted)
+}
+
+// convertWAV mengubah WAV ke format PCM 16-bit tertentu (sample rate dan
+// jumlah kanal), supaya potongan dari engine berbeda bisa disambung dalam
+// satu stream. WAV yang formatnya sudah sama dikembalikan apa adanya.
+func convertWAV(data []byte, format wavFormat) ([]byte, error) {
+	current, _, err := parseWAV(data)
+	if err != nil {
+		return nil, err
+	}
+	if current == format {
+		return data, nil
+	}
+	if format.AudioFormat != 1 || format.BitsPerSample != 16 || format.Channels == 0 {
+		return nil, fmt.Errorf("unsupported target format (need 16-bit PCM)")
+	}
+
+	mono, err := ResamplePCM(data, int(format.SampleRate))
+	if err != nil {
+		return nil, err
+	}
+	channels := int(format.Channels)
+	if channels == 1 {
+		return encodeWAV(format, mono), nil
+	}
+	// Sampel mono disalin ke setiap kanal
+	pcm := make([]byte, 0, len(mono)*channels)
+	for i := 0; i+1 < len(mono); i += 2 {
+		for c := 0; c < channels; c++ {
+			pcm = append(pcm, mono[i], mono[i+1])
+		}
+	}
+	return encodeWAV(format, pcm), nil
+}
+
+// scaleWAV mengalikan amplitudo PCM 16-bit dengan volume (0.0 - 1.0) untuk
+// engine yang tidak punya pengaturan volume sendiri
+func scaleWAV(data []byte, volume float64) ([]byte, error) {
+	format, pcm, err := parseWAV(data)
+	if err != nil {
+		return nil, err
+	}
+	if format.BitsPerSample != 16 {
+		return data, nil
+	}
+
+	scaled := make([]byte, len(pcm))
+	for i := 0; i+1 < len(pcm); i += 2 {
+		sample := float64(int16(binary.LittleEndian.Uint16(pcm[i:]))) * volume
+		binary.LittleEndian.PutUint16(scaled[i:], uint16(int16(sample)))
+	}
+	return encodeWAV(format, scaled), nil
+}
+
+// ResamplePCM mengambil PCM 16-bit mono dari WAV pada sampleRate tertentu
+// (interpolasi linear; kanal stereo dirata-rata), untuk klien yang meminta
+// audio mentah tanpa header
+func ResamplePCM(data []byte, sampleRate int) ([]byte, error) {
+	format, pcm, err := parseWAV(data)
+	if err != nil {
+		return nil, err
+	}
+	if format.AudioFormat != 1 || format.BitsPerSample != 16 || format.Channels == 0 || format.SampleRate == 0 {
+		return nil, fmt.Errorf("unsupported WAV format (need 16-bit PCM)")
+	}
+
+	channels := int(format.Channels)
+	frames := len(pcm) / (2 * channels)
+	mono := make([]float64, frames)
+	for i := range mono {
+		sum := 0.0
+		for c := 0; c < channels; c++ {
+			sum += float64(int16(binary.LittleEndian.Uint16(pcm[(i*channels+c)*2:])))
+		}
+		mono[i] = sum / float64(channels)
+	}
+
+	ratio := float64(format.SampleRate) / float64(sampleRate)
+	count := int(float64(frames) / ratio)
+	out := make([]byte, count*2)
+	for i := 0; i < count; i++ {
+		pos := float64(i) * ratio
+		index := int(pos)
+		sample := mono[index]
+		if index+1 < frames {
+			sample += (mono[index+1] - sample) * (pos - float64(index))
+		}
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(int16(sample)))
+	}
+	return out, nil
+}
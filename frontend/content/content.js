@@ -9,11 +9,15 @@ let settings = {
   cursorEnabled: true,
   cursorSize: 2,
   voiceSpeed: 1.0,
+  symbolVerbosity: "normal",
 };
 
 // Hover tracking
 let hoverTimeout = null;
 let currentSpeech = null;
+let currentAudio = null;
+let speechRequestId = 0;
+let wordHighlightFrame = null;
 let highlightedElement = null;
 let controlPanel = null;
 
@@ -80,6 +84,12 @@ function injectTextOnlyCSS() {
       box-shadow: none !important;
     }
     
+    /* Word being spoken */
+    ::highlight(lansia-word) {
+      background-color: rgba(255, 209, 102, 0.8);
+      color: #000;
+    }
+    
     /* Text resize global */
     html.lansia-text-resized {
       font-size: ${settings.textSize}% !important;
@@ -346,13 +356,23 @@ async function speakText(text) {
 }
 
 async function speakWithBackend(text) {
+  // Minta audio WAV dari backend lalu putar langsung di tab
+  const requestId = ++speechRequestId;
   const response = await fetch("http://localhost:8080/api/tts", {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify({
       text: text,
       speed: settings.voiceSpeed,
-      lang: "id-ID",
+      // Bahasa ditebak backend per kalimat (halaman sering campur Inggris)
+      lang: "auto",
+      output: "audio",
+      marks: true,
+      // Entri leksikon pengucapan bisa khusus untuk situs ini
+      config: {
+        site: location.hostname,
+        verbosity: settings.symbolVerbosity || "normal",
+      },
     }),
   });
 
@@ -360,9 +380,93 @@ async function speakWithBackend(text) {
     throw new Error(`Backend error: ${response.status}`);
   }
 
-  const data = await response.json();
-  console.log("🔊 Backend TTS success:", data);
-  return data;
+  // Audio base64 + timing kata dari backend
+  const result = await response.json();
+  const bytes = Uint8Array.from(atob(result.audio), (c) => c.charCodeAt(0));
+  const blob = new Blob([bytes], { type: result.content_type });
+
+  // Mouse sudah pindah sebelum audio selesai dirender
+  if (requestId !== speechRequestId) return;
+
+  stopSpeech();
+
+  const url = URL.createObjectURL(blob);
+
+  const audio = new Audio(url);
+  audio.onended = () => {
+    URL.revokeObjectURL(url);
+    if (currentAudio === audio) currentAudio = null;
+    clearWordHighlight();
+    removeTextHighlight();
+  };
+
+  currentAudio = audio;
+  await audio.play();
+  followWords(audio, highlightedElement, result.marks || []);
+  console.log("🔊 Backend TTS success:", blob.size, "bytes");
+}
+
+// Sorot kata yang sedang diucapkan mengikuti timing dari backend
+function followWords(audio, element, marks) {
+  if (!element || !window.CSS || !CSS.highlights) return;
+
+  const ranges = mapWordRanges(element, marks);
+  if (ranges.length === 0) return;
+
+  let activeIndex = -1;
+  const step = () => {
+    if (currentAudio !== audio) return;
+
+    const timeMs = audio.currentTime * 1000;
+    const index = ranges.findIndex(
+      ({ mark }) => timeMs >= mark.time_ms && timeMs < mark.time_ms + mark.duration_ms
+    );
+    if (index !== -1 && index !== activeIndex) {
+      activeIndex = index;
+      CSS.highlights.set("lansia-word", new Highlight(ranges[index].range));
+    }
+    wordHighlightFrame = requestAnimationFrame(step);
+  };
+  step();
+}
+
+// Cocokkan tiap mark kata dengan posisinya di text node elemen
+function mapWordRanges(element, marks) {
+  const nodes = Array.from(element.childNodes).filter(
+    (node) => node.nodeType === Node.TEXT_NODE
+  );
+  const ranges = [];
+  let nodeIndex = 0;
+  let offset = 0;
+
+  for (const mark of marks) {
+    if (mark.type !== "word") continue;
+
+    // Kata dicari berurutan, jadi kata yang berulang tetap dapat posisi yang benar
+    for (let i = nodeIndex; i < nodes.length; i++) {
+      const found = nodes[i].textContent.indexOf(mark.text, i === nodeIndex ? offset : 0);
+      if (found === -1) continue;
+
+      const range = document.createRange();
+      range.setStart(nodes[i], found);
+      range.setEnd(nodes[i], found + mark.text.length);
+      ranges.push({ mark, range });
+      nodeIndex = i;
+      offset = found + mark.text.length;
+      break;
+    }
+  }
+  return ranges;
+}
+
+function clearWordHighlight() {
+  if (wordHighlightFrame) {
+    cancelAnimationFrame(wordHighlightFrame);
+    wordHighlightFrame = null;
+  }
+  if (window.CSS && CSS.highlights) {
+    CSS.highlights.delete("lansia-word");
+  }
 }
 
 function speakWithWebAPI(text) {
@@ -385,10 +489,20 @@ function speakWithWebAPI(text) {
 }
 
 function stopSpeech() {
+  speechRequestId++;
+
   if (window.speechSynthesis && window.speechSynthesis.speaking) {
     window.speechSynthesis.cancel();
   }
   currentSpeech = null;
+
+  clearWordHighlight();
+
+  if (currentAudio) {
+    currentAudio.pause();
+    URL.revokeObjectURL(currentAudio.src);
+    currentAudio = null;
+  }
 }
 
 // ============= CONTROL PANEL =============
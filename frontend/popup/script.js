@@ -6,6 +6,7 @@ const state = {
   cursorEnabled: true,
   cursorSize: 2,
   voiceSpeed: 1.0,
+  symbolVerbosity: "normal",
   backendUrl: "http://localhost:8080",
   backendConnected: false,
 };
@@ -16,6 +17,7 @@ const voiceToggle = document.getElementById("voiceToggle");
 const cursorToggle = document.getElementById("cursorToggle");
 const voiceSpeed = document.getElementById("voiceSpeed");
 const speedValue = document.getElementById("speedValue");
+const symbolVerbosity = document.getElementById("symbolVerbosity");
 const cursorSize = document.getElementById("cursorSize");
 const cursorSizeValue = document.getElementById("cursorSizeValue");
 const textSizeValue = document.getElementById("textSizeValue");
@@ -54,6 +56,11 @@ voiceSpeed.addEventListener("input", (e) => {
   saveAndApply();
 });
 
+symbolVerbosity.addEventListener("change", (e) => {
+  state.symbolVerbosity = e.target.value;
+  saveAndApply();
+});
+
 cursorSize.addEventListener("input", (e) => {
   state.cursorSize = parseFloat(e.target.value);
   cursorSizeValue.textContent = `${state.cursorSize}x`;
@@ -108,6 +115,7 @@ function updateUI() {
   cursorToggle.checked = state.cursorEnabled;
   voiceSpeed.value = state.voiceSpeed;
   speedValue.textContent = `${state.voiceSpeed.toFixed(1)}x`;
+  symbolVerbosity.value = state.symbolVerbosity;
   cursorSize.value = state.cursorSize;
   cursorSizeValue.textContent = `${state.cursorSize}x`;
   updateStatusText();